@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func initExportConfigCommand(rootCmd *cobra.Command) {
+	var file string
+
+	exportCmd := &cobra.Command{
+		Use:   "export [profile...]",
+		Short: "Export shareable config bundle",
+		Long: `Exports repositories, backends and profiles as a bundle which can be shared with your team.
+When profiles are given, only they, profiles they extend and repositories and backends they use are exported.
+Tokens and git users are never exported.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			profiles := []issuectl.ProfileName{}
+			for _, name := range args {
+				profiles = append(profiles, issuectl.ProfileName(name))
+			}
+			bundle, err := issuectl.ExportBundle(config, profiles...)
+			if err != nil {
+				return err
+			}
+			data, err := yaml.Marshal(bundle)
+			if err != nil {
+				return err
+			}
+			if file == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+			// same mode as config file, bundle reveals internal hosts and repositories
+			if err := os.WriteFile(file, data, 0600); err != nil {
+				return err
+			}
+			issuectl.Log.Infofp("📦", "Bundle saved to %v", file)
+			return nil
+		},
+	}
+
+	exportCmd.PersistentFlags().StringVarP(&file, "file", "f", "", "File to write bundle to [defaults to stdout]")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func initImportConfigCommand(rootCmd *cobra.Command) {
+	var (
+		file string
+		opts issuectl.ImportOptions
+	)
+
+	importCmd := &cobra.Command{
+		Use:   "import [file or git repository URL]",
+		Short: "Import config bundle",
+		Long: `Merges bundle created with export into config. Bundle can be read from file or git repository.
+Entities which already exist and differ from ones in bundle are reported as conflicts and left unchanged,
+use --overwrite to replace them. Locally configured tokens are kept when backend type doesn't change.
+Import which would leave config invalid is refused unless --force is given. Hooks run arbitrary commands,
+so bundle adding them is imported only with --allow-hooks, after reviewing listed commands.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			bundle, err := issuectl.LoadBundle(cmd.Context(), config, args[0], file)
+			if err != nil {
+				return err
+			}
+			result, err := issuectl.ImportBundle(config.GetPersistent(), bundle, opts)
+			if result != nil {
+				for _, line := range result.Summary() {
+					issuectl.Log.Infofp("📦", "%v", line)
+				}
+				for _, problem := range result.Problems {
+					issuectl.Log.Infofp("⚠️", "%v", problem)
+				}
+				for _, hook := range result.Hooks {
+					issuectl.Log.Infofp("🪝", "hook of %v", hook)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if result.DryRun {
+				issuectl.Log.Infofp("🧪", "Dry run, config not changed")
+			}
+			if len(result.Conflicts) > 0 {
+				return fmt.Errorf("%v conflicting entities left unchanged, use --overwrite to replace them", len(result.Conflicts))
+			}
+			return nil
+		},
+	}
+
+	importCmd.PersistentFlags().StringVarP(&file, "file", "f", "", "Path of bundle in git repository [defaults to "+issuectl.DefaultBundleFileName+"]")
+	importCmd.PersistentFlags().BoolVarP(&opts.DryRun, "dry-run", "", false, "Show what would be imported without changing config")
+	importCmd.PersistentFlags().BoolVarP(&opts.Overwrite, "overwrite", "", false, "Replace existing entities which differ from ones in bundle")
+	importCmd.PersistentFlags().StringVarP((*string)(&opts.GitUser), "gituser", "", "", "Git user set on imported profiles [defaults to the only defined git user]")
+	importCmd.PersistentFlags().BoolVarP(&opts.Force, "force", "", false, "Save config even when import introduces problems")
+	importCmd.PersistentFlags().BoolVarP(&opts.AllowHooks, "allow-hooks", "", false, "Import hooks from bundle, they run arbitrary commands")
+
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+func initCommitCommand(rootCmd *cobra.Command) {
+	var message string
+	var all bool
+	commitCmd := &cobra.Command{
+		Use:   "commit [issue] -m <message>",
+		Short: "Commit staged changes in all repositories of issue",
+		Long: `Commits staged changes in every repository of issue which has them, using the same message
+rendered with commitTemplate of profile or project config [defaults to {{.ID}}: {{.Message}}].
+Commits are made with identity of git user of issue.
+When issue is omitted, issue is detected from current directory or checked out branch.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
+			}
+			return issuectl.CommitIssue(cmd.Context(), config, issueID, message, all)
+		},
+	}
+	commitCmd.Flags().StringVarP(&message, "message", "m", "", "Commit message, prefixed with issue according to commitTemplate")
+	commitCmd.Flags().BoolVarP(&all, "all", "a", false, "Stage all changes, including untracked files, before committing")
+	_ = commitCmd.MarkFlagRequired("message")
+
+	rootCmd.AddCommand(commitCmd)
+}
+
+func initPushCommand(rootCmd *cobra.Command) {
+	var forceWithLease bool
+	pushCmd := &cobra.Command{
+		Use:   "push [issue]",
+		Short: "Push branches of all repositories of issue",
+		Long: `Pushes branch checked out in every repository of issue to origin and reports result for each of them.
+When issue is omitted, issue is detected from current directory or checked out branch.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
+			}
+			return issuectl.PushIssue(cmd.Context(), config, issueID, forceWithLease)
+		},
+	}
+	pushCmd.Flags().BoolVar(&forceWithLease, "force-with-lease", false, "Push branches rewritten by sync, unless remote changed")
+
+	rootCmd.AddCommand(pushCmd)
+}
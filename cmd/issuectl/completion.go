@@ -0,0 +1,138 @@
+package cli
+
+import (
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// argCompletions lists what each positional argument of command is completed with
+var argCompletions = map[string][]completionFunc{
+	"issuectl finish":                   {completeIssues},
+	"issuectl workon":                   {completeIssues},
+	"issuectl openpr":                   {completeIssues},
+	"issuectl status":                   {completeIssues},
+	"issuectl sync":                     {completeIssues},
+	"issuectl exec":                     {completeIssues},
+	"issuectl commit":                   {completeIssues},
+	"issuectl push":                     {completeIssues},
+	"issuectl cd":                       {completeIssues, completeIssueRepositories},
+	"issuectl addRepo":                  {completeRepositories, completeIssues},
+	"issuectl config profile show":      {completeProfiles},
+	"issuectl config profile edit":      {completeProfiles},
+	"issuectl config profile delete":    {completeProfiles},
+	"issuectl config profile use":       {completeProfiles},
+	"issuectl config profile addRepo":   {completeRepositories},
+	"issuectl config export":            {completeProfiles},
+	"issuectl config backend show":      {completeBackends},
+	"issuectl config backend edit":      {completeBackends},
+	"issuectl config backend delete":    {completeBackends},
+	"issuectl config backend use":       {completeBackends},
+	"issuectl config repository show":   {completeRepositories},
+	"issuectl config repository edit":   {completeRepositories},
+	"issuectl config repository delete": {completeRepositories},
+	"issuectl config gituser show":      {completeGitUsers},
+	"issuectl config gituser edit":      {completeGitUsers},
+	"issuectl config gituser delete":    {completeGitUsers},
+}
+
+// flagCompletions lists what flags referencing config entities are completed with
+var flagCompletions = map[string]completionFunc{
+	"profile":       completeProfiles,
+	"extends":       completeProfiles,
+	"repos":         completeRepositories,
+	"default-repo":  completeRepositories,
+	"issue-backend": completeBackends,
+	"repo-backend":  completeBackends,
+	"gituser":       completeGitUsers,
+}
+
+// registerCompletions sets dynamic completions of issue IDs and names of entities from config on all commands
+func registerCompletions(cmd *cobra.Command) {
+	if completions, ok := argCompletions[cmd.CommandPath()]; ok {
+		// export takes any number of profiles
+		variadic := cmd.CommandPath() == "issuectl config export"
+		cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			index := len(args)
+			if variadic {
+				index = 0
+			}
+			if index >= len(completions) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completions[index](cmd, args, toComplete)
+		}
+	}
+
+	for name, complete := range flagCompletions {
+		if cmd.PersistentFlags().Lookup(name) == nil && cmd.LocalNonPersistentFlags().Lookup(name) == nil {
+			continue
+		}
+		if err := cmd.RegisterFlagCompletionFunc(name, complete); err != nil {
+			// completion is best effort, failing to register it shouldn't break the command
+			issuectl.Log.V(2).Infof("Failed to register completion of --%v flag of %v: %v", name, cmd.CommandPath(), err)
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		registerCompletions(child)
+	}
+}
+
+// completeNames loads config and completes names listed by names
+func completeNames(names func(config issuectl.IssuectlConfig, args []string) []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		config, err := loadConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return names(config, args), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// stringKeys returns sorted map keys converted to completion strings
+func stringKeys[K ~string, V any](m map[K]V) []string {
+	keys := []string{}
+	for _, key := range issuectl.SortedKeys(m) {
+		keys = append(keys, string(key))
+	}
+	return keys
+}
+
+var completeIssues = completeNames(func(config issuectl.IssuectlConfig, args []string) []string {
+	completions := []string{}
+	for id, issue := range config.GetIssues() {
+		completions = append(completions, string(id)+"\t"+issue.Name)
+	}
+	return completions
+})
+
+// completeIssueRepositories completes repositories of issue given as first argument
+var completeIssueRepositories = completeNames(func(config issuectl.IssuectlConfig, args []string) []string {
+	issue, found := config.GetIssue(issuectl.IssueID(args[0]))
+	if !found {
+		return nil
+	}
+	repos := []string{}
+	for _, repo := range issue.Repositories {
+		repos = append(repos, string(repo))
+	}
+	return repos
+})
+
+var completeProfiles = completeNames(func(config issuectl.IssuectlConfig, args []string) []string {
+	return stringKeys(config.GetProfiles())
+})
+
+var completeRepositories = completeNames(func(config issuectl.IssuectlConfig, args []string) []string {
+	return stringKeys(config.GetRepositories())
+})
+
+var completeBackends = completeNames(func(config issuectl.IssuectlConfig, args []string) []string {
+	return stringKeys(config.GetBackends())
+})
+
+var completeGitUsers = completeNames(func(config issuectl.IssuectlConfig, args []string) []string {
+	return stringKeys(config.GetGitUsers())
+})
@@ -1,15 +1,12 @@
 package cli
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
-	"text/tabwriter"
+	"io"
 
 	issuectl "github.com/janekbaraniewski/issuectl/pkg"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 )
 
 func initConfigCommand(rootCmd *cobra.Command) {
@@ -24,24 +21,50 @@ func initConfigCommand(rootCmd *cobra.Command) {
 	initRepositoriesCommand(configCmd)
 	initProfileCommand(configCmd)
 	initGitUserCommand(configCmd)
+	initSetConfigCommand(configCmd)
+	initUnsetConfigCommand(configCmd)
+	initExportConfigCommand(configCmd)
+	initImportConfigCommand(configCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
 func initPrintConfigCommand(root *cobra.Command) {
+	var effective bool
+
 	getConfigCmd := &cobra.Command{
 		Use:   "get",
 		Short: "Get config",
-		Long:  `Prints full currently sellected config`,
+		Long: `Prints full currently sellected config, tokens are redacted.
+With --effective current profile includes settings from .issuectl.yaml of current directory.
+Start additionally applies baseBranch from .issuectl.yaml of each cloned repository, while branchTemplate
+of cloned repositories is ignored as branch name is shared by all repositories of issue.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig()
-			confYaml, err := yaml.Marshal(config)
+			config, err := loadConfig()
 			if err != nil {
 				return err
 			}
-			fmt.Printf("%v", string(confYaml))
-			return nil
+			if effective {
+				config, err = issuectl.EffectiveConfig(config)
+				if err != nil {
+					return err
+				}
+			}
+			config, err = issuectl.RedactedConfig(config)
+			if err != nil {
+				return err
+			}
+			return printOutput(config, nil)
 		},
 	}
+
+	getConfigCmd.PersistentFlags().BoolVarP(
+		&effective,
+		"effective",
+		"",
+		false,
+		"Print config with project overrides applied to current profile",
+	)
+
 	root.AddCommand(getConfigCmd)
 }
 
@@ -49,13 +72,16 @@ func initBackendCommand(rootCmd *cobra.Command) {
 	backendCmd := &cobra.Command{
 		Use:   "backend",
 		Short: "Manage backends",
-		Long:  `Manage issuectl backends. You can list, add, delete, and use backends.`,
+		Long:  `Manage issuectl backends. You can list, show, add, edit, delete, and use backends.`,
 	}
 
 	initBackendListCommand(backendCmd)
+	initBackendShowCommand(backendCmd)
 	initBackendAddCommand(backendCmd)
+	initBackendEditCommand(backendCmd)
 	initBackendDeleteCommand(backendCmd)
 	initBackendUseCommand(backendCmd)
+	initBackendMigrateSecretsCommand(backendCmd)
 
 	rootCmd.AddCommand(backendCmd)
 }
@@ -64,13 +90,21 @@ func initBackendListCommand(rootCmd *cobra.Command) {
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all backends",
-		Run: func(cmd *cobra.Command, args []string) {
-			w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-			fmt.Fprintln(w, "NAME\tTYPE\t")
-			for _, backend := range issuectl.LoadConfig().GetBackends() {
-				fmt.Fprintln(w, fmt.Sprintf("%v\t%v\t", backend.Name, backend.Type)) //nolint:gosimple
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			backends := []*issuectl.BackendConfig{}
+			for _, backend := range issuectl.SortedValues(config.GetBackends()) {
+				backends = append(backends, issuectl.RedactSecrets(backend))
 			}
-			w.Flush()
+			return printOutput(backends, func(w io.Writer) {
+				fmt.Fprintln(w, "NAME\tTYPE\t")
+				for _, backend := range backends {
+					fmt.Fprintf(w, "%v\t%v\t\n", backend.Name, backend.Type)
+				}
+			})
 		},
 	}
 
@@ -89,13 +123,17 @@ func initBackendAddCommand(rootCmd *cobra.Command) {
 	}
 
 	var flags *_flags = &_flags{}
+	tokenFlags := &tokenSourceFlags{}
 
 	addCmd := &cobra.Command{
 		Use:   "add [name] [type]",
 		Short: "Add a new backend",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig().GetPersistent()
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			backendName := args[0]
 			_backendType := args[1]
 
@@ -109,7 +147,7 @@ func initBackendAddCommand(rootCmd *cobra.Command) {
 			switch backendType {
 
 			case issuectl.BackendGithub:
-				token := base64.RawStdEncoding.EncodeToString([]byte(flags.GitHubToken))
+				token := issuectl.EncodeToken(flags.GitHubToken)
 				githubConfig := &issuectl.GitHubConfig{
 					Host:  flags.GitHubApi,
 					Token: token,
@@ -117,7 +155,7 @@ func initBackendAddCommand(rootCmd *cobra.Command) {
 				newBackend.GitHub = githubConfig
 
 			case issuectl.BackendGitLab:
-				token := base64.RawStdEncoding.EncodeToString([]byte(flags.GitLabToken))
+				token := issuectl.EncodeToken(flags.GitLabToken)
 				gitlabConfig := &issuectl.GitLabConfig{
 					Host:  flags.GitLabApi,
 					Token: token,
@@ -125,7 +163,7 @@ func initBackendAddCommand(rootCmd *cobra.Command) {
 				newBackend.GitLab = gitlabConfig
 
 			case issuectl.BackendJira:
-				token := base64.RawStdEncoding.EncodeToString([]byte(flags.JiraToken))
+				token := issuectl.EncodeToken(flags.JiraToken)
 				jiraBackend := &issuectl.JiraConfig{
 					Host:     flags.JiraHost,
 					Token:    token,
@@ -133,7 +171,10 @@ func initBackendAddCommand(rootCmd *cobra.Command) {
 				}
 				newBackend.Jira = jiraBackend
 			}
-			return config.AddBackend(&newBackend)
+			if err := tokenFlags.apply(cmd, &newBackend); err != nil {
+				return err
+			}
+			return config.GetPersistent().AddBackend(&newBackend)
 		},
 	}
 
@@ -193,6 +234,8 @@ func initBackendAddCommand(rootCmd *cobra.Command) {
 		"Jira API Username",
 	)
 
+	tokenFlags.register(addCmd)
+
 	rootCmd.AddCommand(addCmd)
 }
 
@@ -202,9 +245,12 @@ func initBackendDeleteCommand(rootCmd *cobra.Command) {
 		Short: "Delete a backend",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig().GetPersistent()
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			backendName := args[0]
-			return config.DeleteBackend(issuectl.BackendConfigName(backendName))
+			return config.GetPersistent().DeleteBackend(issuectl.BackendConfigName(backendName))
 		},
 	}
 
@@ -214,29 +260,141 @@ func initBackendDeleteCommand(rootCmd *cobra.Command) {
 func initBackendUseCommand(rootCmd *cobra.Command) {
 	useCmd := &cobra.Command{
 		Use:   "use [name]",
-		Short: "Use a backend",
-		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			// config := issuectl.LoadConfig()
+		Short: "Use a backend in current profile",
+		Long: `Sets backend as issue backend of current profile and, if it supports pull requests,
+as its repo backend.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			backend := config.GetBackend(issuectl.BackendConfigName(args[0]))
+			if backend == nil {
+				return fmt.Errorf("backend %v not defined", args[0])
+			}
+			profile := config.GetProfile(config.GetCurrentProfile())
+			if profile == nil {
+				return fmt.Errorf("profile %v not defined", config.GetCurrentProfile())
+			}
+
+			if backend.Type.Supports(issuectl.CapabilityIssueTracking) {
+				profile.IssueBackend = backend.Name
+				issuectl.Log.Infofp("📋", "Using %v as issue backend of profile %v", backend.Name, profile.Name)
+			}
+			if backend.Type.Supports(issuectl.CapabilityPullRequests) {
+				profile.RepoBackend = backend.Name
+				issuectl.Log.Infofp("📂", "Using %v as repo backend of profile %v", backend.Name, profile.Name)
+			}
+			if err := issuectl.ValidateProfileBackends(config, profile); err != nil {
+				return err
+			}
+			return config.GetPersistent().UpdateProfile(profile)
 		},
 	}
 
 	rootCmd.AddCommand(useCmd)
 }
 
+func initBackendShowCommand(rootCmd *cobra.Command) {
+	showCmd := &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show a backend",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			backend := config.GetBackend(issuectl.BackendConfigName(args[0]))
+			if backend == nil {
+				return fmt.Errorf("backend %v not defined", args[0])
+			}
+			return printEntity(issuectl.RedactSecrets(backend))
+		},
+	}
+
+	rootCmd.AddCommand(showCmd)
+}
+
+func initBackendEditCommand(rootCmd *cobra.Command) {
+	var token string
+	tokenFlags := &tokenSourceFlags{}
+
+	editCmd := &cobra.Command{
+		Use:   "edit [name]",
+		Short: "Edit a backend",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			backend := config.GetBackend(issuectl.BackendConfigName(args[0]))
+			if backend == nil {
+				return fmt.Errorf("backend %v not defined", args[0])
+			}
+
+			config = config.GetInMemory()
+			path := fmt.Sprintf("backends.%v.%v", backend.Name, backend.Type)
+			changed, err := setFields(cmd, config, path, map[string]string{
+				"host":     "host",
+				"username": "username",
+				"user-id":  "userID",
+			})
+			if err != nil {
+				return err
+			}
+
+			backend = config.GetBackend(backend.Name)
+			if cmd.Flags().Changed("token") {
+				if err := config.Set(path+".token", issuectl.EncodeToken(token)); err != nil {
+					return err
+				}
+				backend = config.GetBackend(backend.Name)
+				backend.TokenSource = nil
+				changed = true
+			}
+			if *tokenFlags != (tokenSourceFlags{}) {
+				if err := tokenFlags.apply(cmd, backend); err != nil {
+					return err
+				}
+				changed = true
+			}
+			if !changed {
+				return fmt.Errorf("nothing to change, use flags to set new values")
+			}
+			return config.GetPersistent().Save()
+		},
+	}
+
+	editCmd.PersistentFlags().StringP("host", "", "", "API URL")
+	editCmd.PersistentFlags().StringP("username", "", "", "Username (GitHub and Jira)")
+	editCmd.PersistentFlags().IntP("user-id", "", 0, "User ID (GitLab)")
+	editCmd.PersistentFlags().StringVarP(&token, "token", "", "", "API token stored in config file")
+	tokenFlags.register(editCmd)
+
+	rootCmd.AddCommand(editCmd)
+}
+
 func initRepoListCommand(rootCmd *cobra.Command) {
 	repoListCmd := &cobra.Command{
 		Use:                "list",
 		Short:              "List all repositories",
 		Long:               `List all repositories`,
 		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
-		Run: func(cmd *cobra.Command, args []string) {
-			w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-			fmt.Fprintln(w, "NAME\tOWNER\tURL\t")
-			for _, repo := range issuectl.LoadConfig().GetRepositories() {
-				fmt.Fprintln(w, fmt.Sprintf("%v\t%v\t%v\t", repo.Name, repo.Owner, repo.RepoURL)) //nolint:gosimple
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
 			}
-			w.Flush()
+			repos := issuectl.SortedValues(config.GetRepositories())
+			return printOutput(repos, func(w io.Writer) {
+				fmt.Fprintln(w, "NAME\tOWNER\tURL\t")
+				for _, repo := range repos {
+					fmt.Fprintf(w, "%v\t%v\t%v\t\n", repo.Name, repo.Owner, repo.RepoURL)
+				}
+			})
 		},
 	}
 
@@ -256,19 +414,83 @@ func initRepoAddCommand(rootCmd *cobra.Command) {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conf := issuectl.LoadConfig().GetPersistent()
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			repoConfig := &issuectl.RepoConfig{
 				Owner:   args[0],
 				Name:    issuectl.RepoConfigName(args[1]),
 				RepoURL: issuectl.RepoURL(args[2]),
 			}
-			return conf.AddRepository(repoConfig)
+			return conf.GetPersistent().AddRepository(repoConfig)
 		},
 	}
 
 	rootCmd.AddCommand(repoAddCmd)
 }
 
+func initRepoShowCommand(rootCmd *cobra.Command) {
+	showCmd := &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show a repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			repo := config.GetRepository(issuectl.RepoConfigName(args[0]))
+			if repo == nil {
+				return fmt.Errorf("repository %v not defined", args[0])
+			}
+			return printEntity(repo)
+		},
+	}
+
+	rootCmd.AddCommand(showCmd)
+}
+
+func initRepoEditCommand(rootCmd *cobra.Command) {
+	editCmd := &cobra.Command{
+		Use:   "edit [name]",
+		Short: "Edit a repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return editEntity(cmd, config, "repositories."+args[0], map[string]string{
+				"owner": "owner",
+				"url":   "url",
+			})
+		},
+	}
+
+	editCmd.PersistentFlags().StringP("owner", "", "", "Repository owner")
+	editCmd.PersistentFlags().StringP("url", "", "", "Repository URL")
+
+	rootCmd.AddCommand(editCmd)
+}
+
+func initRepoDeleteCommand(rootCmd *cobra.Command) {
+	deleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return config.GetPersistent().DeleteRepository(issuectl.RepoConfigName(args[0]))
+		},
+	}
+
+	rootCmd.AddCommand(deleteCmd)
+}
+
 func initRepositoriesCommand(rootCmd *cobra.Command) {
 	repoCmd := &cobra.Command{
 		Use:                "repository",
@@ -282,7 +504,10 @@ func initRepositoriesCommand(rootCmd *cobra.Command) {
 	}
 
 	initRepoListCommand(repoCmd)
+	initRepoShowCommand(repoCmd)
 	initRepoAddCommand(repoCmd)
+	initRepoEditCommand(repoCmd)
+	initRepoDeleteCommand(repoCmd)
 	rootCmd.AddCommand(repoCmd)
 }
 
@@ -290,13 +515,18 @@ func initGitUserListCommand(rootCmd *cobra.Command) {
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all Git users",
-		Run: func(cmd *cobra.Command, args []string) {
-			w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-			fmt.Fprintln(w, "NAME\tEMAIL\tSSH KEY\t")
-			for _, user := range issuectl.LoadConfig().GetGitUsers() {
-				fmt.Fprintln(w, fmt.Sprintf("%v\t%v\t%v\t", user.Name, user.Email, user.SSHKey)) //nolint:gosimple
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
 			}
-			w.Flush()
+			users := issuectl.SortedValues(config.GetGitUsers())
+			return printOutput(users, func(w io.Writer) {
+				fmt.Fprintln(w, "NAME\tEMAIL\tSSH KEY\t")
+				for _, user := range users {
+					fmt.Fprintf(w, "%v\t%v\t%v\t\n", user.Name, user.Email, user.SSHKey)
+				}
+			})
 		},
 	}
 
@@ -309,13 +539,16 @@ func initGitUserAddCommand(rootCmd *cobra.Command) {
 		Short: "Add a new Git user",
 		Args:  cobra.ExactArgs(3), // Expects exactly 3 arguments
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conf := issuectl.LoadConfig().GetPersistent()
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			gitUser := &issuectl.GitUser{
 				Name:   issuectl.GitUserName(args[0]),
 				Email:  args[1],
 				SSHKey: args[2],
 			}
-			return conf.AddGitUser(gitUser)
+			return conf.GetPersistent().AddGitUser(gitUser)
 		},
 	}
 
@@ -324,27 +557,76 @@ func initGitUserAddCommand(rootCmd *cobra.Command) {
 
 func initGitUserDeleteCommand(rootCmd *cobra.Command) {
 	deleteCmd := &cobra.Command{
-		Use:   "delete",
+		Use:   "delete [username]",
 		Short: "Delete a Git user",
 		Args:  cobra.ExactArgs(1), // Expects exactly 1 argument
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conf := issuectl.LoadConfig()
-			return conf.DeleteGitUser(issuectl.GitUserName(args[0]))
+			conf, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return conf.GetPersistent().DeleteGitUser(issuectl.GitUserName(args[0]))
 		},
 	}
 
 	rootCmd.AddCommand(deleteCmd)
 }
 
+func initGitUserShowCommand(rootCmd *cobra.Command) {
+	showCmd := &cobra.Command{
+		Use:   "show [username]",
+		Short: "Show a Git user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			user, found := config.GetGitUser(issuectl.GitUserName(args[0]))
+			if !found {
+				return fmt.Errorf("git user %v not defined", args[0])
+			}
+			return printEntity(user)
+		},
+	}
+
+	rootCmd.AddCommand(showCmd)
+}
+
+func initGitUserEditCommand(rootCmd *cobra.Command) {
+	editCmd := &cobra.Command{
+		Use:   "edit [username]",
+		Short: "Edit a Git user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return editEntity(cmd, config, "gitUsers."+args[0], map[string]string{
+				"email":   "email",
+				"ssh-key": "sshKey",
+			})
+		},
+	}
+
+	editCmd.PersistentFlags().StringP("email", "", "", "Git user email")
+	editCmd.PersistentFlags().StringP("ssh-key", "", "", "Path to SSH key")
+
+	rootCmd.AddCommand(editCmd)
+}
+
 func initGitUserCommand(rootCmd *cobra.Command) {
 	userCmd := &cobra.Command{
 		Use:   "gituser",
 		Short: "Manage Git users",
-		Long:  `Manage Git users. You can list, add, delete Git users.`,
+		Long:  `Manage Git users. You can list, show, add, edit, delete Git users.`,
 	}
 
 	initGitUserListCommand(userCmd)
+	initGitUserShowCommand(userCmd)
 	initGitUserAddCommand(userCmd)
+	initGitUserEditCommand(userCmd)
 	initGitUserDeleteCommand(userCmd)
 
 	rootCmd.AddCommand(userCmd)
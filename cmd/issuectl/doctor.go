@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+func initDoctorCommand(rootCmd *cobra.Command) {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check config and environment for problems",
+		Long: `Validates references in config and checks everything issuectl depends on:
+git availability, SSH keys of git users, profile work directories and backend credentials.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				issuectl.Log.Infofp("❌", "config cannot be loaded: %v", err)
+				return fmt.Errorf("config can't be loaded")
+			}
+
+			failed := 0
+			for _, result := range issuectl.Diagnose(cmd.Context(), config) {
+				if result.Skipped != "" {
+					issuectl.Log.Infofp("⏭️", "%v: skipped, %v", result.Check, result.Skipped)
+					continue
+				}
+				if result.Err != nil {
+					failed++
+					issuectl.Log.Infofp("❌", "%v: %v", result.Check, result.Err)
+					continue
+				}
+				issuectl.Log.Infofp("✅", "%v", result.Check)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("doctor found %v problem(s)", failed)
+			}
+			issuectl.Log.Infofp("👍", "All good!")
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// editEntity sets fields of config entity at path for each changed flag and saves config.
+// Flags are mapped to yaml names of fields.
+func editEntity(cmd *cobra.Command, config issuectl.IssuectlConfig, path string, fields map[string]string) error {
+	config = config.GetInMemory()
+	changed, err := setFields(cmd, config, path, fields)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("nothing to change, use flags to set new values")
+	}
+	return config.GetPersistent().Save()
+}
+
+// setFields sets fields of config entity at path for each changed flag, reports if anything was changed
+func setFields(cmd *cobra.Command, config issuectl.IssuectlConfig, path string, fields map[string]string) (bool, error) {
+	changed := false
+	for flagName, field := range fields {
+		if !cmd.Flags().Changed(flagName) {
+			continue
+		}
+		value, err := flagValue(cmd, flagName)
+		if err != nil {
+			return false, err
+		}
+		encoded, err := yaml.Marshal(value)
+		if err != nil {
+			return false, err
+		}
+		if err := config.Set(path+"."+field, string(encoded)); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+func flagValue(cmd *cobra.Command, name string) (interface{}, error) {
+	switch cmd.Flags().Lookup(name).Value.Type() {
+	case "stringSlice":
+		return cmd.Flags().GetStringSlice(name)
+	case "stringArray":
+		return cmd.Flags().GetStringArray(name)
+	case "bool":
+		return cmd.Flags().GetBool(name)
+	case "int":
+		return cmd.Flags().GetInt(name)
+	default:
+		return cmd.Flags().GetString(name)
+	}
+}
+
+// printEntity prints config entity in format selected with --output, YAML for table format
+func printEntity(entity interface{}) error {
+	return printOutput(entity, nil)
+}
+
+func initSetConfigCommand(rootCmd *cobra.Command) {
+	setCmd := &cobra.Command{
+		Use:   "set [path] [value]",
+		Short: "Set config value",
+		Long: `Sets config value at dot separated path. Value is parsed as YAML.
+
+	issuectl config set profiles.work.workDir /home/me/work
+	issuectl config set profiles.work.repositories "[api, web]"
+	issuectl config set currentProfile work
+
+Changes leaving references to entities which are not defined are rejected.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return config.GetPersistent().Set(args[0], args[1])
+		},
+	}
+
+	rootCmd.AddCommand(setCmd)
+}
+
+func initUnsetConfigCommand(rootCmd *cobra.Command) {
+	unsetCmd := &cobra.Command{
+		Use:   "unset [path]",
+		Short: "Unset config value",
+		Long: `Removes config value at dot separated path.
+
+	issuectl config unset profiles.work.baseBranch
+
+Removing entities still referenced by profiles or issues is rejected.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return config.GetPersistent().Unset(args[0])
+		},
+	}
+
+	rootCmd.AddCommand(unsetCmd)
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+func initExecCommand(rootCmd *cobra.Command) {
+	var opts issuectl.ExecOptions
+	execCmd := &cobra.Command{
+		Use:   "exec [issue] -- <command> [args...]",
+		Short: "Run command in every repository of issue",
+		Long: `Runs command in directory of each repository of issue, e.g. 'issuectl exec -- go test ./...'.
+Single argument is run with sh -c, so 'issuectl exec -- "git log --oneline | head -3"' works.
+Output lines are prefixed with repository name, --group prints output of each repository together instead.
+Exit status is the highest one of failed commands.
+When issue is omitted, issue is detected from current directory or checked out branch.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return fmt.Errorf("command to run has to be given after --")
+			}
+			if dash > 1 {
+				return fmt.Errorf("accepts at most 1 issue before --, received %v", dash)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// failure of executed command isn't a usage problem
+			cmd.SilenceUsage = true
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			dash := cmd.ArgsLenAtDash()
+			issueID, err := issueIDFromArgs(cmd, config, args[:dash], 0)
+			if err != nil {
+				return err
+			}
+			return issuectl.ExecInIssue(cmd.Context(), config, issueID, args[dash:], opts, os.Stdout)
+		},
+	}
+	execCmd.Flags().BoolVarP(&opts.Parallel, "parallel", "p", false, "Run command in all repositories at once")
+	execCmd.Flags().BoolVarP(&opts.Group, "group", "g", false, "Print output of each repository together after command finishes there")
+
+	rootCmd.AddCommand(execCmd)
+}
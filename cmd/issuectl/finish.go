@@ -11,16 +11,24 @@ func initFinishCommand(rootCmd *cobra.Command) {
 	finishCmd := &cobra.Command{
 		Use:                "finish [issue number]",
 		Short:              "Cleanup resources and close issue",
-		Long:               `Removes issue work directory. Closes issue in backend.`,
+		Long:               `Removes issue work directory. Closes issue in backend. Issue is detected from current directory when not provided.`,
 		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
-				return errors.New("you must provide exactly 1 argument - issue id")
+			if len(args) > 1 {
+				return errors.New("you can provide at most 1 argument - issue id")
 			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := issuectl.FinishWorkingOnIssue(issuectl.IssueID(args[0])); err != nil {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
+			}
+			if err := issuectl.FinishWorkingOnIssue(cmd.Context(), config.GetPersistent(), issueID); err != nil {
 				return err
 			}
 
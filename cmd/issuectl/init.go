@@ -142,7 +142,7 @@ func askForGitHubConfig() (issuectl.GitHubConfig, error) {
 	}
 	return issuectl.GitHubConfig{
 		Host:     answers.Host,
-		Token:    answers.Token,
+		Token:    issuectl.EncodeToken(answers.Token),
 		Username: answers.Username,
 	}, nil
 }
@@ -179,7 +179,7 @@ func askForGitLabConfig() (issuectl.GitLabConfig, error) {
 	}
 	return issuectl.GitLabConfig{
 		Host:   answers.Host,
-		Token:  answers.Token,
+		Token:  issuectl.EncodeToken(answers.Token),
 		UserID: userID,
 	}, nil
 }
@@ -212,11 +212,33 @@ func askForJiraConfig() (issuectl.JiraConfig, error) {
 	}
 	return issuectl.JiraConfig{
 		Host:     answers.Host,
-		Token:    answers.Token,
+		Token:    issuectl.EncodeToken(answers.Token),
 		Username: answers.Username,
 	}, nil
 }
 
+func askToStoreTokenInVault(cmd *cobra.Command, backend *issuectl.BackendConfig) error {
+	useVault := true
+	prompt := &survey.Confirm{
+		Message: "Do you want to store token in encrypted vault instead of config file?",
+		Default: true,
+	}
+	if err := survey.AskOne(prompt, &useVault); err != nil {
+		return err
+	}
+	if !useVault {
+		return nil
+	}
+	vault, err := issuectl.OpenVault(issuectl.DefaultVaultFilePath)
+	if err != nil {
+		return err
+	}
+	if err := issuectl.MoveTokenToVault(cmd.Context(), vault, backend); err != nil {
+		return err
+	}
+	return vault.Save()
+}
+
 func askForProfile() (issuectl.Profile, error) {
 	answers := struct {
 		Workdir string
@@ -283,8 +305,11 @@ func initInitConfigCommand(rootCmd *cobra.Command) {
 		Use:   "init",
 		Short: "Initialize configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			configPath := issuectl.DefaultConfigFilePath
-			_, err := os.Stat(configPath)
+			configPath, err := issuectl.ResolveConfigPath(Global.Config)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stat(configPath)
 			if err == nil {
 				return fmt.Errorf("config file already exists at %s", configPath)
 			}
@@ -307,6 +332,9 @@ func initInitConfigCommand(rootCmd *cobra.Command) {
 				if err != nil {
 					return err
 				}
+				if err := askToStoreTokenInVault(cmd, &backend); err != nil {
+					return err
+				}
 			}
 
 			profile, err := askForProfile()
@@ -345,7 +373,7 @@ func initInitConfigCommand(rootCmd *cobra.Command) {
 				},
 			)
 
-			return config.GetPersistent().Save()
+			return config.WithPath(configPath).GetPersistent().Save()
 		},
 	}
 	rootCmd.AddCommand(initCmd)
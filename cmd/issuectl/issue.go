@@ -9,12 +9,20 @@ func initAddRepoToIssueCommand(rootCmd *cobra.Command) {
 	listCmd := &cobra.Command{
 		Use:   "addRepo [repo name] [issueID]",
 		Short: "Add repo to issue. Clones repository to issue workdir and sets up branch.",
-		Args:  cobra.ExactArgs(2),
+		Long:  `Clones repository to issue workdir and sets up branch. Issue is detected from current directory when not provided.`,
+		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			repoName := args[0]
-			issueID := args[1] // TODO: this should be also figured out from context to just run `i addRepo someOtherRepo` while inside issue workdir
 
-			return issuectl.AddRepoToIssue(repoName, issuectl.IssueID(issueID))
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 1)
+			if err != nil {
+				return err
+			}
+			return issuectl.AddRepoToIssue(cmd.Context(), config.GetPersistent(), repoName, issueID)
 
 		},
 	}
@@ -2,11 +2,9 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"text/tabwriter"
+	"io"
 
 	issuectl "github.com/janekbaraniewski/issuectl/pkg"
-
 	"github.com/spf13/cobra"
 )
 
@@ -15,21 +13,23 @@ func initListIssuesCommand(rootCmd *cobra.Command) {
 		Use:   "list",
 		Short: "List all issues",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			issues := issuectl.LoadConfig().GetIssues() // Load your configuration here
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issues := issuectl.SortedValues(config.GetIssues())
 
-			if len(issues) == 0 {
+			if len(issues) == 0 && Global.Output == string(issuectl.OutputTable) {
 				fmt.Println("No issues found.")
 				return nil
 			}
 
-			w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-			fmt.Fprintln(w, "ID\tName\tRepository Backend\tIssue Backend\tRepositories\tProfile\t")
-			for issueID, issue := range issues {
-				fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t\n", issueID, issue.Name, issue.RepoBackend, issue.IssueBackend, issue.Repositories, issue.Profile)
-			}
-			w.Flush()
-
-			return nil
+			return printOutput(issues, func(w io.Writer) {
+				fmt.Fprintln(w, "ID\tName\tRepository Backend\tIssue Backend\tRepositories\tProfile\t")
+				for _, issue := range issues {
+					fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t\n", issue.ID, issue.Name, issue.RepoBackend, issue.IssueBackend, issue.Repositories, issue.Profile)
+				}
+			})
 		},
 	}
 
@@ -2,42 +2,73 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 
 	issuectl "github.com/janekbaraniewski/issuectl/pkg"
-
 	"github.com/spf13/cobra"
 )
 
-const CodeEditorVSCode string = "code"
-
 func initWorkonIssueCommand(rootCmd *cobra.Command) {
+	var (
+		launcher issuectl.LauncherConfig
+		save     bool
+		session  bool
+	)
+
 	var openIssueCmd = &cobra.Command{
 		Use:   "workon [issueID]",
 		Short: "Open specified issue in the preferred code editor",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig() // Load your configuration here
-			issueID := issuectl.IssueID(args[0])
+		Long: `Opens issue in editor configured by launcher of issue or its profile, falling back to $VISUAL, $EDITOR and VS Code.
+Issue is detected from current directory when not provided.
 
+	issuectl workon XY-69 --launcher goland
+	issuectl workon XY-69 --launcher nvim --launcher-arg "{{.Dir}}" --save
+	issuectl workon XY-69 --workspace
+
+With --session, tmux or zellij session named after issue is opened instead, with window for each repository.
+Multiplexer, layouts and startup commands are configured in session of profile.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
+			}
 			issue, found := config.GetIssue(issueID)
 			if !found {
 				return fmt.Errorf("Issue %s not found", issueID)
 			}
-
-			// Open the preferred editor with the directory IssueConfig.Dir
-			openCmd := exec.Command(CodeEditorVSCode, issue.Dir) // Change 'code' to your preferred editor
-			openCmd.Stdin = os.Stdin
-			openCmd.Stdout = os.Stdout
-			err := openCmd.Run()
-			if err != nil {
-				return fmt.Errorf("Failed to open editor: %v", err)
+			if session {
+				return issuectl.OpenIssueSession(cmd.Context(), config, issueID)
 			}
 
-			return nil
+			var override *issuectl.LauncherConfig
+			if cmd.Flags().Changed("launcher") || cmd.Flags().Changed("launcher-arg") || cmd.Flags().Changed("workspace") {
+				override = &launcher
+				if override.Command == "" {
+					override.Command = issuectl.IssueLauncher(config, issue).Command
+				}
+			}
+			if save {
+				if override == nil {
+					return fmt.Errorf("--save requires --launcher, --launcher-arg or --workspace")
+				}
+				issue.Launcher = override
+				if err := config.GetPersistent().AddIssue(issue); err != nil {
+					return err
+				}
+			}
+			return issuectl.LaunchIssue(cmd.Context(), config, issueID, override)
 		},
 	}
 
+	openIssueCmd.PersistentFlags().StringVarP(&launcher.Command, "launcher", "", "", "Command opening issue, e.g. goland, cursor or nvim")
+	openIssueCmd.PersistentFlags().StringArrayVarP(&launcher.Args, "launcher-arg", "", []string{}, "Go template of launcher argument, can be repeated")
+	openIssueCmd.PersistentFlags().BoolVarP(&launcher.Workspace, "workspace", "", false, "Generate .code-workspace file with issue repositories and open it")
+	openIssueCmd.PersistentFlags().BoolVarP(&session, "session", "s", false, "Create or attach to tmux or zellij session of issue")
+	openIssueCmd.PersistentFlags().BoolVarP(&save, "save", "", false, "Store launcher in issue config for next runs")
+
 	rootCmd.AddCommand(openIssueCmd)
 }
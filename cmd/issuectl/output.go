@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+)
+
+// printOutput prints value in format selected with --output.
+// Table format is printed with table, commands without table print YAML instead.
+func printOutput(value interface{}, table func(w io.Writer)) error {
+	format := issuectl.OutputFormat(Global.Output)
+	if format == issuectl.OutputTable {
+		if table == nil {
+			return issuectl.WriteOutput(os.Stdout, issuectl.OutputYAML, "", value)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+	return issuectl.WriteOutput(os.Stdout, format, Global.Template, value)
+}
+
+// validateOutputFlags checks values of --output and --template
+func validateOutputFlags() error {
+	format, err := issuectl.ParseOutputFormat(Global.Output)
+	if err != nil {
+		return err
+	}
+	if format == issuectl.OutputTemplate && Global.Template == "" {
+		return fmt.Errorf("--output template requires --template")
+	}
+	if format != issuectl.OutputTemplate && Global.Template != "" {
+		return fmt.Errorf("--template can only be used with --output template")
+	}
+	return nil
+}
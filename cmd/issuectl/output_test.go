@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// captureStdout runs fn and returns everything it wrote to stdout
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+// TestConfigGetJSONOutput tests that stdout of `config get -o json` is valid JSON
+// even when loading config logs, e.g. while migrating old config version.
+func TestConfigGetJSONOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "currentProfile: default\ngitUsers:\n  John Doe:\n    name: John Doe\n    email: john@doe.com\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %s", err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		cmd := RootCmd("test")
+		cmd.SetArgs([]string{"config", "get", "--config", path, "-o", "json"})
+		err = cmd.Execute()
+	})
+	if err != nil {
+		t.Fatalf("config get failed: %s", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("stdout is not valid JSON: %s\n%s", err, out)
+	}
+	if _, found := parsed["gitUsers"]; !found {
+		t.Errorf("expected gitUsers in output, got:\n%s", out)
+	}
+}
+
+// TestCdPrintsOnlyPath tests that stdout of `cd` contains only issue directory used by shell integration.
+func TestCdPrintsOnlyPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	issueDir := filepath.Join(dir, "42")
+	config := "currentProfile: default\nissues:\n  \"42\":\n    id: \"42\"\n    name: test\n    dir: " + issueDir + "\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %s", err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		cmd := RootCmd("test")
+		cmd.SetArgs([]string{"cd", "42", "--config", path})
+		err = cmd.Execute()
+	})
+	if err != nil {
+		t.Fatalf("cd failed: %s", err)
+	}
+	if string(out) != issueDir+"\n" {
+		t.Errorf("expected stdout to be only %q, got %q", issueDir, out)
+	}
+}
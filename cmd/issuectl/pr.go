@@ -1,67 +1,50 @@
 package cli
 
 import (
-	"errors"
 	"os"
-	"path/filepath"
-	"strings"
 
 	issuectl "github.com/janekbaraniewski/issuectl/pkg"
 	"github.com/spf13/cobra"
 )
 
-func isSubdir(baseDir, checkDir string) (string, error) {
-	// Get the relative path from baseDir to checkDir
-	relativePath, err := filepath.Rel(baseDir, checkDir)
-	if err != nil {
-		return "", err
-	}
-
-	// Check if the relative path starts with ".."
-	isSubdir := !strings.HasPrefix(relativePath, "..")
-	if !isSubdir {
-		return "", nil
+// issueIDFromArgs returns issue ID passed as argument at index or, when it's missing, issue detected from current directory
+func issueIDFromArgs(cmd *cobra.Command, config issuectl.IssuectlConfig, args []string, index int) (issuectl.IssueID, error) {
+	if len(args) > index {
+		return issuectl.IssueID(args[index]), nil
 	}
-	return relativePath, nil
-}
-
-func getIssueIDFromParentDirectory(config issuectl.IssuectlConfig) string {
-	profile := config.GetProfile(config.GetCurrentProfile())
 	dir, err := os.Getwd()
 	if err != nil {
-		return ""
+		return "", err
 	}
-
-	isSub, err := isSubdir(profile.WorkDir, dir)
-	if err != nil || isSub == "" {
-		return ""
+	issueID, err := issuectl.ResolveIssueID(cmd.Context(), config, "", dir)
+	if err != nil {
+		return "", err
 	}
-	issueID := strings.Split(isSub, "/")[0]
-	return issueID
+	issuectl.Log.Infofp("🔎", "Using issue %v detected from current directory", issueID)
+	return issueID, nil
 }
 
 func initOpenPullRequestCommand(rootCmd *cobra.Command) {
 	openPRCmd := &cobra.Command{
 		Use:   "openpr [issue number] [pr title]",
 		Short: "Opens a pull request for the specified issue. You can specify title, if left empty default title will be generated from issue title",
-		Long:  `This command opens a pull request for the specified issue in Repository Backend`, // FIXME: Github??                                                 // it requires exactly one argument
+		Long: `This command opens a pull request for the specified issue in Repository Backend.
+When issue number is omitted, issue is detected from current directory or checked out branch.`, // FIXME: Github??
+		Args: cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig()
-			var issueID string
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			var customTitle string
-			if len(args) == 1 {
-				issueID = args[0]
-			} else if len(args) == 0 {
-				// check if can detect issue from pwd
-				issueID = getIssueIDFromParentDirectory(config)
-			} else if len(args) == 2 {
-				issueID = args[0]
+			if len(args) == 2 {
 				customTitle = args[1]
 			}
-			if issueID == "" {
-				return errors.New("Missing issueID")
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
 			}
-			err := issuectl.OpenPullRequest(issuectl.IssueID(issueID), customTitle)
+			err = issuectl.OpenPullRequest(cmd.Context(), config, issueID, customTitle)
 			if err != nil {
 				return err
 			}
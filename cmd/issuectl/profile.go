@@ -2,25 +2,29 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"text/tabwriter"
+	"io"
+	"sort"
+	"strings"
 
 	issuectl "github.com/janekbaraniewski/issuectl/pkg"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 )
 
 func initProfileCommand(rootCmd *cobra.Command) {
 	profileCmd := &cobra.Command{
 		Use:   "profile",
 		Short: "Manage profiles",
-		Long:  `Manage issuectl profiles. You can list, add, delete, and use profiles.`,
+		Long:  `Manage issuectl profiles. You can list, show, add, edit, delete, and use profiles.`,
 	}
 
 	initProfileListCommand(profileCmd)
 	initProfileAddCommand(profileCmd)
+	initProfileEditCommand(profileCmd)
 	initProfileDeleteCommand(profileCmd)
 	initProfileUseCommand(profileCmd)
 	initProfileAddRepoCommand(profileCmd)
+	initProfileShowCommand(profileCmd)
 
 	rootCmd.AddCommand(profileCmd)
 }
@@ -29,22 +33,18 @@ func initProfileListCommand(rootCmd *cobra.Command) {
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all profiles",
-		Run: func(cmd *cobra.Command, args []string) {
-			config := issuectl.LoadConfig()
-			profiles := config.GetProfiles()
-			w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
-			fmt.Fprintln(w, "NAME\tWORK DIR\tGIT USER\tREPOSITORIES\t")
-			for _, profile := range profiles {
-				repos := []string{}
-				for _, repoName := range profile.Repositories {
-					repos = append(repos, string(repoName))
-				}
-				fmt.Fprintln(w, fmt.Sprintf( //nolint
-					"%v\t%v\t%v\t%v\t",
-					profile.Name, profile.WorkDir, profile.GitUserName, repos,
-				))
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
 			}
-			w.Flush()
+			profiles := issuectl.SortedValues(config.GetProfiles())
+			return printOutput(profiles, func(w io.Writer) {
+				fmt.Fprintln(w, "NAME\tWORK DIR\tGIT USER\tREPOSITORIES\t")
+				for _, profile := range profiles {
+					fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", profile.Name, profile.WorkDir, profile.GitUserName, profile.Repositories)
+				}
+			})
 		},
 	}
 
@@ -52,12 +52,17 @@ func initProfileListCommand(rootCmd *cobra.Command) {
 }
 
 func initProfileAddCommand(rootCmd *cobra.Command) {
+	var extends string
+
 	addCmd := &cobra.Command{
 		Use:   "add [name] [workdir] [issue backend] [repo backend] [git user] [default repo]",
 		Short: "Add a new profile",
 		Args:  cobra.ExactArgs(6),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig().GetPersistent()
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			profileName := args[0]
 			workDir := args[1]
 			issueBackend := args[2]
@@ -70,6 +75,7 @@ func initProfileAddCommand(rootCmd *cobra.Command) {
 			}
 			newProfile := &issuectl.Profile{
 				Name:              issuectl.ProfileName(profileName),
+				Extends:           issuectl.ProfileName(extends),
 				WorkDir:           workDir,
 				Repositories:      repos,
 				IssueBackend:      issuectl.BackendConfigName(issueBackend),
@@ -77,7 +83,10 @@ func initProfileAddCommand(rootCmd *cobra.Command) {
 				GitUserName:       issuectl.GitUserName(gitUser),
 				DefaultRepository: issuectl.RepoConfigName(defaultRepo),
 			}
-			return config.AddProfile(newProfile)
+			if err := issuectl.ValidateProfileBackends(config, newProfile); err != nil {
+				return err
+			}
+			return config.GetPersistent().AddProfile(newProfile)
 		},
 	}
 
@@ -89,18 +98,84 @@ func initProfileAddCommand(rootCmd *cobra.Command) {
 		"A list of repositories to clone",
 	)
 
+	addCmd.PersistentFlags().StringVarP(
+		&extends,
+		"extends",
+		"",
+		"",
+		"Name of profile to inherit settings from",
+	)
+
 	rootCmd.AddCommand(addCmd)
 }
 
+func initProfileEditCommand(rootCmd *cobra.Command) {
+	editCmd := &cobra.Command{
+		Use:   "edit [name]",
+		Short: "Edit a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return editEntity(cmd, config, "profiles."+args[0], map[string]string{
+				"workdir":            "workDir",
+				"issue-backend":      "issueBackend",
+				"repo-backend":       "repoBackend",
+				"gituser":            "gituser",
+				"default-repo":       "defaultRepository",
+				"repos":              "repositories",
+				"extends":            "extends",
+				"merge-lists":        "mergeLists",
+				"base-branch":        "baseBranch",
+				"branch-template":    "branchTemplate",
+				"pr-template":        "prTemplate",
+				"sync-strategy":      "syncStrategy",
+				"commit-template":    "commitTemplate",
+				"git-client":         "gitClient",
+				"launcher":           "launcher.command",
+				"launcher-arg":       "launcher.args",
+				"launcher-workspace": "launcher.workspace",
+				"template-dir":       "templateDir",
+			})
+		},
+	}
+
+	editCmd.PersistentFlags().StringP("workdir", "", "", "Directory issues are started in")
+	editCmd.PersistentFlags().StringP("issue-backend", "", "", "Name of issue backend")
+	editCmd.PersistentFlags().StringP("repo-backend", "", "", "Name of repo backend")
+	editCmd.PersistentFlags().StringP("gituser", "", "", "Name of git user")
+	editCmd.PersistentFlags().StringP("default-repo", "", "", "Name of default repository")
+	editCmd.PersistentFlags().StringSliceP("repos", "r", []string{}, "A list of repositories to clone, replaces current list")
+	editCmd.PersistentFlags().StringP("extends", "", "", "Name of profile to inherit settings from")
+	editCmd.PersistentFlags().StringP("merge-lists", "", "", "How inherited lists are merged: append or replace")
+	editCmd.PersistentFlags().StringP("base-branch", "", "", "Branch pull requests are opened against")
+	editCmd.PersistentFlags().StringP("branch-template", "", "", "Go template of branch name")
+	editCmd.PersistentFlags().StringP("pr-template", "", "", "Go template of pull request body")
+	editCmd.PersistentFlags().StringP("sync-strategy", "", "", "How sync updates issue branches: rebase or merge")
+	editCmd.PersistentFlags().StringP("commit-template", "", "", "Go template of commit messages")
+	editCmd.PersistentFlags().StringP("git-client", "", "", "How git operations are done: exec runs git, go-git works without it")
+	editCmd.PersistentFlags().StringP("launcher", "", "", "Command opening issues in editor, e.g. goland or nvim")
+	editCmd.PersistentFlags().StringArrayP("launcher-arg", "", []string{}, "Go template of launcher argument, can be repeated")
+	editCmd.PersistentFlags().StringP("template-dir", "", "", "Directory with files copied to new issue directories")
+	editCmd.PersistentFlags().BoolP("launcher-workspace", "", false, "Generate .code-workspace file with issue repositories before launching")
+
+	rootCmd.AddCommand(editCmd)
+}
+
 func initProfileDeleteCommand(rootCmd *cobra.Command) {
 	deleteCmd := &cobra.Command{
 		Use:   "delete [name]",
 		Short: "Delete a profile",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig().GetPersistent()
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			profileName := args[0]
-			return config.DeleteProfile(issuectl.ProfileName(profileName))
+			return config.GetPersistent().DeleteProfile(issuectl.ProfileName(profileName))
 		},
 	}
 
@@ -113,9 +188,12 @@ func initProfileUseCommand(rootCmd *cobra.Command) {
 		Short: "Use a profile",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig().GetPersistent()
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
 			profileName := args[0]
-			return config.UseProfile(issuectl.ProfileName(profileName))
+			return config.GetPersistent().UseProfile(issuectl.ProfileName(profileName))
 		},
 	}
 
@@ -128,7 +206,11 @@ func initProfileAddRepoCommand(rootCmd *cobra.Command) {
 		Short: "Add a new repository to current profile",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config := issuectl.LoadConfig().GetPersistent()
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			config = config.GetPersistent()
 			repoName := args[0]
 			profile := config.GetProfile(config.GetCurrentProfile())
 			if err := profile.AddRepository((issuectl.RepoConfigName)(repoName)); err != nil {
@@ -144,3 +226,57 @@ func initProfileAddRepoCommand(rootCmd *cobra.Command) {
 
 	rootCmd.AddCommand(addRepoCmd)
 }
+
+func initProfileShowCommand(rootCmd *cobra.Command) {
+	showCmd := &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show profile with inherited settings",
+		Long:  `Shows profile settings resolved through extends chain and profile each setting comes from. Defaults to current profile.`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			profileName := config.GetCurrentProfile()
+			if len(args) == 1 {
+				profileName = issuectl.ProfileName(args[0])
+			}
+			profile, origins, err := config.ResolveProfile(profileName)
+			if err != nil {
+				return err
+			}
+
+			values := map[string]interface{}{}
+			data, err := yaml.Marshal(profile)
+			if err != nil {
+				return err
+			}
+			if err := yaml.Unmarshal(data, &values); err != nil {
+				return err
+			}
+
+			resolved := struct {
+				Profile *issuectl.Profile       `yaml:"profile"`
+				Origins issuectl.ProfileOrigins `yaml:"origins"`
+			}{profile, origins}
+			return printOutput(resolved, func(w io.Writer) {
+				fmt.Fprintln(w, "SETTING\tVALUE\tFROM\t")
+				fields := make([]string, 0, len(values))
+				for field := range values {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				for _, field := range fields {
+					from := []string{}
+					for _, origin := range origins[field] {
+						from = append(from, string(origin))
+					}
+					fmt.Fprintf(w, "%v\t%v\t%v\t\n", field, values[field], strings.Join(from, ", "))
+				}
+			})
+		},
+	}
+
+	rootCmd.AddCommand(showCmd)
+}
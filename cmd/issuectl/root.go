@@ -1,9 +1,15 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
 	"github.com/spf13/cobra"
 )
 
@@ -23,17 +29,74 @@ Finish work, close the issue
 `
 )
 
+type GlobalFlags struct {
+	Config   string
+	Timeout  time.Duration
+	Output   string
+	Template string
+}
+
+var Global GlobalFlags
+
+// cancelTimeout releases resources of context created for --timeout
+var cancelTimeout context.CancelFunc = func() {}
+
 func RootCmd(version string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "issuectl",
 		Version: version,
 		Short:   ShortDescription,
 		Long:    LongDescription,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFlags(); err != nil {
+				return err
+			}
+			if Global.Timeout > 0 {
+				ctx, cancel := context.WithTimeout(cmd.Context(), Global.Timeout)
+				cancelTimeout = cancel
+				cmd.SetContext(ctx)
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 
 		},
 	}
 
+	cmd.PersistentFlags().StringVarP(
+		&Global.Config,
+		"config",
+		"",
+		"",
+		"Path to config file [defaults to $ISSUECTL_CONFIG or $XDG_CONFIG_HOME/issuectl/config.yaml]",
+	)
+
+	cmd.PersistentFlags().DurationVarP(
+		&Global.Timeout,
+		"timeout",
+		"",
+		0,
+		"Maximum time the command can take, e.g. 30s or 5m [defaults to no timeout]",
+	)
+
+	cmd.PersistentFlags().StringVarP(
+		&Global.Output,
+		"output",
+		"o",
+		string(issuectl.OutputTable),
+		"Output format of listing commands, one of table, json, yaml or template",
+	)
+
+	cmd.PersistentFlags().StringVarP(
+		&Global.Template,
+		"template",
+		"",
+		"",
+		"Go template used with --output template, e.g. '{{range .}}{{.name}}{{\"\\n\"}}{{end}}'",
+	)
+
+	issuectl.VaultPassphrase = askForVaultPassphrase
+
 	initStartCommand(cmd)
 	initFinishCommand(cmd)
 	initOpenPullRequestCommand(cmd)
@@ -42,12 +105,54 @@ func RootCmd(version string) *cobra.Command {
 	initListIssuesCommand(cmd)
 	initWorkonIssueCommand(cmd)
 	initAddRepoToIssueCommand(cmd)
+	initDoctorCommand(cmd)
+	initShellInitCommand(cmd)
+	initCdCommand(cmd)
+	initPromptCommand(cmd)
+	initStatusCommand(cmd)
+	initSyncCommand(cmd)
+	initExecCommand(cmd)
+	initCommitCommand(cmd)
+	initPushCommand(cmd)
+	registerCompletions(cmd)
 	return cmd
 }
 
+// loadConfig loads config from file selected with --config, $ISSUECTL_CONFIG or default location
+func loadConfig() (issuectl.IssuectlConfig, error) {
+	path, err := issuectl.ResolveConfigPath(Global.Config)
+	if err != nil {
+		return nil, err
+	}
+	return issuectl.LoadConfig(path)
+}
+
 func Execute(version string) {
-	if err := RootCmd(version).Execute(); err != nil {
+	// First interrupt cancels the context so that commands can stop gracefully,
+	// the next one falls back to default behaviour and kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := RootCmd(version).ExecuteContext(ctx)
+	cancelTimeout()
+	stop()
+
+	var execErr *issuectl.ExecError
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &execErr):
+		// error was already printed, exit status of command run with exec is kept
+		os.Exit(execErr.ExitCode())
+	case errors.Is(err, context.Canceled):
+		fmt.Fprintf(os.Stderr, "Interrupted, stopped before finishing the command\n")
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Fprintf(os.Stderr, "Command didn't finish within %v timeout\n", Global.Timeout)
+	default:
 		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing your CLI '%s'", err)
-		os.Exit(1)
 	}
+	os.Exit(1)
 }
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+// askForVaultPassphrase reads vault passphrase from environment or prompts for it
+func askForVaultPassphrase(create bool) (string, error) {
+	if passphrase := os.Getenv(issuectl.EnvVaultPassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+
+	message := "Enter vault passphrase:"
+	if create {
+		message = "Enter passphrase for new vault:"
+	}
+	passphrase := ""
+	if err := survey.AskOne(&survey.Password{Message: message}, &passphrase, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+	if !create {
+		return passphrase, nil
+	}
+
+	confirmation := ""
+	if err := survey.AskOne(&survey.Password{Message: "Repeat passphrase:"}, &confirmation); err != nil {
+		return "", err
+	}
+	if confirmation != passphrase {
+		return "", fmt.Errorf("passphrases don't match")
+	}
+	return passphrase, nil
+}
+
+type tokenSourceFlags struct {
+	Env           string
+	Command       string
+	GitCredential string
+	Vault         bool
+}
+
+func (f *tokenSourceFlags) register(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&f.Env, "token-env", "", "", "Read token from environment variable")
+	cmd.PersistentFlags().StringVarP(&f.Command, "token-command", "", "", "Read token from output of shell command, e.g. `pass show github`")
+	cmd.PersistentFlags().StringVarP(&f.GitCredential, "token-git-credential", "", "", "Read token from git credential helper for URL, e.g. https://github.com")
+	cmd.PersistentFlags().BoolVarP(&f.Vault, "vault", "", false, "Store token in encrypted vault instead of config file")
+}
+
+// apply configures token source of backend according to flags
+func (f *tokenSourceFlags) apply(cmd *cobra.Command, backend *issuectl.BackendConfig) error {
+	source := &issuectl.SecretSource{Env: f.Env, Command: f.Command, GitCredential: f.GitCredential}
+	if *source != (issuectl.SecretSource{}) {
+		if f.Vault {
+			return fmt.Errorf("--vault can't be used together with other token sources")
+		}
+		if err := source.Validate(); err != nil {
+			return err
+		}
+		backend.TokenSource = source
+		backend.ClearStoredToken()
+		return nil
+	}
+
+	if !f.Vault {
+		return nil
+	}
+	if !backend.HasStoredToken() {
+		return fmt.Errorf("--vault requires token to store")
+	}
+	vault, err := issuectl.OpenVault(issuectl.DefaultVaultFilePath)
+	if err != nil {
+		return err
+	}
+	if err := issuectl.MoveTokenToVault(cmd.Context(), vault, backend); err != nil {
+		return err
+	}
+	return vault.Save()
+}
+
+func initBackendMigrateSecretsCommand(rootCmd *cobra.Command) {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate-secrets",
+		Short: "Move backend tokens from config file to encrypted vault",
+		Long: `Moves tokens stored in config file to encrypted vault and points backends to it.
+Backends with token source already configured are left untouched.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			backends := config.GetBackends()
+			toMigrate := []*issuectl.BackendConfig{}
+			for _, backend := range backends {
+				if backend.TokenSource == nil && backend.HasStoredToken() {
+					toMigrate = append(toMigrate, backend)
+				}
+			}
+			if len(toMigrate) == 0 {
+				issuectl.Log.Infofp("👍", "No tokens stored in config file")
+				return nil
+			}
+
+			vault, err := issuectl.OpenVault(issuectl.DefaultVaultFilePath)
+			if err != nil {
+				return err
+			}
+			for _, backend := range toMigrate {
+				if err := issuectl.MoveTokenToVault(cmd.Context(), vault, backend); err != nil {
+					return err
+				}
+			}
+			// vault has to be saved first so that tokens aren't lost if saving config fails
+			if err := vault.Save(); err != nil {
+				return err
+			}
+			if err := config.GetPersistent().Save(); err != nil {
+				return err
+			}
+			if err := os.Chmod(config.GetPath(), 0600); err != nil {
+				return err
+			}
+
+			for _, backend := range toMigrate {
+				issuectl.Log.Infofp("🔐", "Moved token of backend %v to vault", backend.Name)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+// EnvShellIntegration is set by shell wrapper function when it runs issuectl cd
+const EnvShellIntegration = "ISSUECTL_SHELL_INTEGRATION"
+
+const defaultPromptFormat = "{{.id}}{{if .dirty}}*{{end}}"
+
+// shellWrappers are functions which let `issuectl cd` change directory of the shell and print prompt segment
+var shellWrappers = map[string]string{
+	"bash": posixShellWrapper,
+	"zsh":  posixShellWrapper,
+	"fish": `function issuectl
+    if test "$argv[1]" = cd
+        set -l dir (env ` + EnvShellIntegration + `=1 command issuectl $argv)
+        and builtin cd -- $dir
+    else
+        command issuectl $argv
+    end
+end
+
+function issuectl_prompt
+    command issuectl prompt 2>/dev/null
+end
+`,
+}
+
+const posixShellWrapper = `issuectl() {
+    if [ "$1" = "cd" ]; then
+        local dir
+        dir="$(` + EnvShellIntegration + `=1 command issuectl "$@")" && builtin cd -- "$dir"
+    else
+        command issuectl "$@"
+    fi
+}
+
+issuectl_prompt() {
+    command issuectl prompt 2>/dev/null
+}
+`
+
+func initShellInitCommand(rootCmd *cobra.Command) {
+	shellInitCmd := &cobra.Command{
+		Use:   "shell-init [bash|zsh|fish]",
+		Short: "Print shell integration script",
+		Long: `Prints shell function wrapping issuectl, so that 'issuectl cd' changes directory of your shell,
+'issuectl_prompt' function printing current issue for your prompt and completions.
+
+Add to your shell config:
+	bash: eval "$(issuectl shell-init bash)"
+	zsh:  eval "$(issuectl shell-init zsh)"
+	fish: issuectl shell-init fish | source
+
+And, to show current issue in prompt:
+	bash: PS1='$(issuectl_prompt) '$PS1
+	zsh:  setopt PROMPT_SUBST; PROMPT='$(issuectl_prompt) '$PROMPT`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wrapper, ok := shellWrappers[args[0]]
+			if !ok {
+				return fmt.Errorf("unsupported shell %v, use one of bash, zsh or fish", args[0])
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprint(out, wrapper)
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletionV2(out, true)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(out)
+			default:
+				return cmd.Root().GenFishCompletion(out, true)
+			}
+		},
+	}
+
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func initCdCommand(rootCmd *cobra.Command) {
+	cdCmd := &cobra.Command{
+		Use:   "cd [issueID] [repo name]",
+		Short: "Change directory to issue or its repository",
+		Long: `Prints directory of issue or its repository. With shell integration set up by 'issuectl shell-init'
+your shell changes directory to it. Issue is detected from current directory when not provided.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			// stdout has to contain only the path as shell integration changes directory to it,
+			// anything else is written to stderr
+			var issueID issuectl.IssueID
+			if len(args) > 0 {
+				issueID = issuectl.IssueID(args[0])
+			}
+			issueID, err = issuectl.ResolveIssueID(cmd.Context(), config, issueID, dir)
+			if err != nil {
+				return err
+			}
+			var repo issuectl.RepoConfigName
+			if len(args) > 1 {
+				repo = issuectl.RepoConfigName(args[1])
+			}
+			path, err := issuectl.IssuePath(config, issueID, repo)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			if os.Getenv(EnvShellIntegration) == "" {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Shell integration isn't set up, see 'issuectl shell-init --help' to let issuectl cd change directory")
+			}
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(cdCmd)
+}
+
+func initPromptCommand(rootCmd *cobra.Command) {
+	var format string
+
+	promptCmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print current issue for shell prompt",
+		Long: `Prints issue current directory belongs to, nothing outside of issues. Only local state is read.
+Format is a Go template with fields id, name, profile, branch and dirty.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			status, err := issuectl.GetIssueStatus(cmd.Context(), config, dir)
+			if errors.Is(err, issuectl.ErrIssueNotDetected) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return issuectl.WriteOutput(cmd.OutOrStdout(), issuectl.OutputTemplate, format, status)
+		},
+	}
+
+	promptCmd.PersistentFlags().StringVarP(&format, "format", "", defaultPromptFormat, "Go template of prompt segment")
+
+	rootCmd.AddCommand(promptCmd)
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	issuectl "github.com/janekbaraniewski/issuectl/pkg"
 	"github.com/spf13/cobra"
@@ -18,32 +19,33 @@ type CLIOverwrites struct {
 
 var Flags CLIOverwrites
 
-func MergeConfigWithOverwrites(conf issuectl.IssuectlConfig, overwrites *CLIOverwrites) (issuectl.IssuectlConfig, error) {
-	conf = conf.GetInMemory()
-
+// ProfileWithOverwrites returns copy of selected profile with CLI overrides applied.
+// Overrides are used only for the command, config itself is never changed.
+func ProfileWithOverwrites(conf issuectl.IssuectlConfig, overwrites *CLIOverwrites) (*issuectl.Profile, error) {
+	profileName := conf.GetCurrentProfile()
 	if overwrites.Profile != "" {
-		if err := conf.UseProfile(issuectl.ProfileName(overwrites.Profile)); err != nil {
-			return conf, err
-		}
+		profileName = issuectl.ProfileName(overwrites.Profile)
 	}
 
-	overwriteProfile := conf.GetProfile(conf.GetCurrentProfile())
+	overwriteProfile := conf.GetProfile(profileName)
 	if overwriteProfile == nil {
-		return conf, fmt.Errorf("Failed - profile %v not defined.", overwrites.Profile)
+		return nil, fmt.Errorf("Failed - profile %v not defined.", profileName)
 	}
 	if overwrites.IssueBackend != "" {
 		overwriteProfile.IssueBackend = issuectl.BackendConfigName(overwrites.IssueBackend)
 	}
-	if overwrites.Repos != nil {
-		for _, repoName := range overwrites.Repos {
-			overwriteProfile.Repositories = append(overwriteProfile.Repositories, issuectl.RepoConfigName(repoName))
-		}
+	if overwrites.RepoBackend != "" {
+		overwriteProfile.RepoBackend = issuectl.BackendConfigName(overwrites.RepoBackend)
 	}
-
-	if err := conf.UpdateProfile(overwriteProfile); err != nil {
+	if err := issuectl.ValidateProfileBackends(conf, overwriteProfile); err != nil {
 		return nil, err
 	}
-	return conf, nil
+	for _, repoName := range overwrites.Repos {
+		if !slices.Contains(overwriteProfile.Repositories, issuectl.RepoConfigName(repoName)) {
+			overwriteProfile.Repositories = append(overwriteProfile.Repositories, issuectl.RepoConfigName(repoName))
+		}
+	}
+	return overwriteProfile, nil
 }
 
 func initStartCommand(rootCmd *cobra.Command) {
@@ -59,12 +61,15 @@ func initStartCommand(rootCmd *cobra.Command) {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config, err := MergeConfigWithOverwrites(issuectl.LoadConfig(), &Flags)
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			profile, err := ProfileWithOverwrites(config, &Flags)
 			if err != nil {
 				return err
 			}
-			if err := issuectl.StartWorkingOnIssue(Flags.IssueName, config.GetPersistent(), issuectl.IssueID(args[0])); err != nil {
-				// TODO: rollback changes made by StartWorkingOnIssue
+			if err := issuectl.StartWorkingOnIssueWithProfile(cmd.Context(), Flags.IssueName, config.GetPersistent(), profile, issuectl.IssueID(args[0])); err != nil {
 				return err
 			}
 
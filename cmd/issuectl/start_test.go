@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+)
+
+// newBareRepo creates bare git repository with single commit and returns its path
+func newBareRepo(t *testing.T, name string) string {
+	t.Helper()
+	root := t.TempDir()
+	bare := filepath.Join(root, name+".git")
+	work := filepath.Join(root, name)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=issuectl", "-c", "user.email=issuectl@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	git(root, "init", "--bare", "--initial-branch=master", bare)
+	git(root, "clone", bare, work)
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# "+name+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write README: %s", err)
+	}
+	git(work, "add", "README.md")
+	git(work, "commit", "-m", "Initial commit")
+	git(work, "push", "origin", "HEAD:master")
+	return bare
+}
+
+// TestStartOverridesArentSaved tests that --profile and --repos of start apply only to started issue.
+func TestStartOverridesArentSaved(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	config := issuectl.GetEmptyConfig().WithPath(path).GetPersistent()
+	for _, name := range []issuectl.RepoConfigName{"api", "extra"} {
+		repo := &issuectl.RepoConfig{Name: name, Owner: "owner", RepoURL: issuectl.RepoURL(newBareRepo(t, string(name)))}
+		if err := config.AddRepository(repo); err != nil {
+			t.Fatalf("AddRepository() failed: %s", err)
+		}
+	}
+	if err := config.AddGitUser(&issuectl.GitUser{Name: "tester", Email: "tester@example.com", SSHKey: "/dev/null"}); err != nil {
+		t.Fatalf("AddGitUser() failed: %s", err)
+	}
+	for _, name := range []issuectl.ProfileName{"work", "other"} {
+		profile := &issuectl.Profile{
+			Name:              name,
+			WorkDir:           filepath.Join(dir, string(name)),
+			GitUserName:       "tester",
+			Repositories:      []issuectl.RepoConfigName{"api"},
+			DefaultRepository: "api",
+		}
+		if err := os.MkdirAll(profile.WorkDir, 0755); err != nil {
+			t.Fatalf("failed to create workdir: %s", err)
+		}
+		if err := config.AddProfile(profile); err != nil {
+			t.Fatalf("AddProfile() failed: %s", err)
+		}
+	}
+	if err := config.UseProfile("work"); err != nil {
+		t.Fatalf("UseProfile() failed: %s", err)
+	}
+
+	for _, id := range []string{"7", "8"} {
+		cmd := RootCmd("test")
+		cmd.SetArgs([]string{"start", id, "--config", path, "-p", "other", "-r", "extra"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("start %v failed: %s", id, err)
+		}
+	}
+
+	reloaded, err := issuectl.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %s", err)
+	}
+	if current := reloaded.GetCurrentProfile(); current != "work" {
+		t.Errorf("expected current profile to stay work, got %v", current)
+	}
+	if repos := reloaded.GetProfile("other").Repositories; !reflect.DeepEqual(repos, []issuectl.RepoConfigName{"api"}) {
+		t.Errorf("expected repositories of profile to stay [api], got %v", repos)
+	}
+	issue, found := reloaded.GetIssue("8")
+	if !found {
+		t.Fatalf("issue not saved")
+	}
+	if issue.Profile != "other" || !reflect.DeepEqual(issue.Repositories, []issuectl.RepoConfigName{"api", "extra"}) {
+		t.Errorf("expected issue with profile other and repositories [api extra], got %v %v", issue.Profile, issue.Repositories)
+	}
+}
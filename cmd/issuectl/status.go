@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+func initStatusCommand(rootCmd *cobra.Command) {
+	var fetch bool
+	statusCmd := &cobra.Command{
+		Use:   "status [issue]",
+		Short: "Show status of all repositories of issue",
+		Long: `Reports for each repository of issue its current branch, commits ahead and behind upstream and base branch,
+uncommitted changes, last commit and state of pull request with its CI checks.
+When issue is omitted, issue is detected from current directory or checked out branch.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
+			}
+			report, err := issuectl.GetIssueReport(cmd.Context(), config, issueID, fetch)
+			if err != nil {
+				return err
+			}
+
+			return printOutput(report, func(w io.Writer) {
+				fmt.Fprintf(w, "Issue %v: %v [%v]\n\n", report.ID, report.Name, report.Branch)
+				fmt.Fprintln(w, "Repository\tBranch\tUpstream\tBase\tChanges\tLast commit\tPull request\tChecks\t")
+				for _, repo := range report.Repositories {
+					fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n",
+						repo.Name,
+						repo.Branch,
+						formatComparison(repo.Upstream),
+						formatComparison(repo.Base),
+						len(repo.DirtyFiles),
+						repo.LastCommit,
+						formatPullRequest(repo.PullRequest),
+						formatChecks(repo.PullRequest),
+					)
+				}
+				for _, repo := range report.Repositories {
+					for _, problem := range repo.Errors {
+						fmt.Fprintf(w, "\n⚠️  %v: %v", repo.Name, problem)
+					}
+				}
+				fmt.Fprintln(w)
+			})
+		},
+	}
+	statusCmd.Flags().BoolVar(&fetch, "fetch", false, "Fetch remotes before comparing branches")
+
+	rootCmd.AddCommand(statusCmd)
+}
+
+func formatComparison(comparison *issuectl.BranchComparison) string {
+	if comparison == nil {
+		return "-"
+	}
+	return fmt.Sprintf("↑%v ↓%v %v", comparison.Ahead, comparison.Behind, comparison.Ref)
+}
+
+func formatPullRequest(pr *issuectl.PullRequestStatus) string {
+	if pr == nil {
+		return "-"
+	}
+	return fmt.Sprintf("#%v %v", pr.Number, pr.State)
+}
+
+// formatChecks counts checks by state
+func formatChecks(pr *issuectl.PullRequestStatus) string {
+	if pr == nil || len(pr.Checks) == 0 {
+		return "-"
+	}
+	counts := map[string]int{}
+	for _, check := range pr.Checks {
+		counts[check.State]++
+	}
+	states := stringKeys(counts)
+	sort.Strings(states)
+	summary := []string{}
+	for _, state := range states {
+		summary = append(summary, fmt.Sprintf("%v %v", counts[state], state))
+	}
+	return strings.Join(summary, ", ")
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	issuectl "github.com/janekbaraniewski/issuectl/pkg"
+	"github.com/spf13/cobra"
+)
+
+func initSyncCommand(rootCmd *cobra.Command) {
+	var strategy string
+	var resume bool
+	syncCmd := &cobra.Command{
+		Use:   "sync [issue]",
+		Short: "Update issue branches with changes from base branch",
+		Long: `Fetches every repository of issue and rebases or merges issue branch onto its base branch.
+Strategy is taken from --strategy, project config or profile syncStrategy [defaults to rebase].
+Repositories with conflicts are left with rebase or merge in progress and listed at the end,
+resolve conflicts, git add files and run 'issuectl sync --continue'.
+When issue is omitted, issue is detected from current directory or checked out branch.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			issueID, err := issueIDFromArgs(cmd, config, args, 0)
+			if err != nil {
+				return err
+			}
+			return issuectl.SyncIssue(cmd.Context(), config, issueID, strategy, resume)
+		},
+	}
+	syncCmd.Flags().StringVar(&strategy, "strategy", "", "How issue branches are updated: rebase or merge")
+	syncCmd.Flags().BoolVar(&resume, "continue", false, "Continue rebases and merges stopped on resolved conflicts")
+
+	rootCmd.AddCommand(syncCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/google/go-github/github"
 	"golang.org/x/oauth2"
@@ -16,7 +17,7 @@ type GitHub struct {
 	client  *github.Client
 }
 
-func NewGitHubClient(token, baseURL, user string) *GitHub {
+func NewGitHubClient(token, baseURL, user string) (*GitHub, error) {
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
@@ -24,7 +25,23 @@ func NewGitHubClient(token, baseURL, user string) *GitHub {
 	tc := oauth2.NewClient(ctx, ts)
 
 	client := github.NewClient(tc)
-	return &GitHub{client: client, baseURL: baseURL, token: token}
+	if baseURL != "" {
+		enterpriseClient, err := github.NewEnterpriseClient(baseURL, baseURL, tc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		client = enterpriseClient
+	}
+	return &GitHub{client: client, baseURL: baseURL, token: token, user: user}, nil
+}
+
+// webURL returns address of GitHub web UI, API path of enterprise host is dropped
+func (g *GitHub) webURL() string {
+	if g.baseURL == "" {
+		return "https://github.com"
+	}
+	webURL := strings.TrimSuffix(g.baseURL, "/")
+	return strings.TrimSuffix(webURL, "/api/v3")
 }
 
 func (g *GitHub) GetIssueURL(owner string, repo RepoConfigName, issueID IssueID) (string, error) {
@@ -33,16 +50,16 @@ func (g *GitHub) GetIssueURL(owner string, repo RepoConfigName, issueID IssueID)
 		return "", err
 	}
 
-	return fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, issueNumber), nil
+	return fmt.Sprintf("%s/%s/%s/issues/%d", g.webURL(), owner, repo, issueNumber), nil
 }
 
-func (g *GitHub) GetIssue(owner string, repo RepoConfigName, issueID IssueID) (interface{}, error) {
+func (g *GitHub) GetIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) (interface{}, error) {
 	issueNumber, err := getIssueNumberFromString(issueID)
 	if err != nil {
 		return nil, err
 	}
 
-	issue, _, err := g.client.Issues.Get(context.Background(), owner, string(repo), issueNumber)
+	issue, _, err := g.client.Issues.Get(ctx, owner, string(repo), issueNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -50,14 +67,14 @@ func (g *GitHub) GetIssue(owner string, repo RepoConfigName, issueID IssueID) (i
 	return issue, nil
 }
 
-func (g *GitHub) CloseIssue(owner string, repo RepoConfigName, issueID IssueID) error {
+func (g *GitHub) CloseIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error {
 	issueNumber, err := getIssueNumberFromString(issueID)
 	if err != nil {
 		return err
 	}
 
 	issueRequest := &github.IssueRequest{State: github.String("closed")}
-	_, _, err = g.client.Issues.Edit(context.Background(), owner, string(repo), issueNumber, issueRequest)
+	_, _, err = g.client.Issues.Edit(ctx, owner, string(repo), issueNumber, issueRequest)
 	if err != nil {
 		return err
 	}
@@ -65,7 +82,7 @@ func (g *GitHub) CloseIssue(owner string, repo RepoConfigName, issueID IssueID)
 	return nil
 }
 
-func (g *GitHub) OpenPullRequest(owner string, repo RepoConfigName, title, body, baseBranch, headBranch string) (*int, error) {
+func (g *GitHub) OpenPullRequest(ctx context.Context, owner string, repo RepoConfigName, title, body, baseBranch, headBranch string) (*int, error) {
 	newPR := &github.NewPullRequest{
 		Title:               github.String(title),
 		Head:                github.String(headBranch),
@@ -74,7 +91,7 @@ func (g *GitHub) OpenPullRequest(owner string, repo RepoConfigName, title, body,
 		MaintainerCanModify: github.Bool(true),
 	}
 
-	pr, _, err := g.client.PullRequests.Create(context.Background(), owner, string(repo), newPR)
+	pr, _, err := g.client.PullRequests.Create(ctx, owner, string(repo), newPR)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +99,7 @@ func (g *GitHub) OpenPullRequest(owner string, repo RepoConfigName, title, body,
 	return pr.Number, nil
 }
 
-func (g *GitHub) LinkIssueToRepo(owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error {
+func (g *GitHub) LinkIssueToRepo(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error {
 	// Convert the pull request ID from string to int
 	pullRequestNumber, err := strconv.Atoi(pullRequestID)
 	if err != nil {
@@ -95,7 +112,7 @@ func (g *GitHub) LinkIssueToRepo(owner string, repo RepoConfigName, issueID Issu
 	}
 
 	// Post the comment to the pull request
-	_, _, err = g.client.Issues.CreateComment(context.Background(), owner, string(repo), pullRequestNumber, comment)
+	_, _, err = g.client.Issues.CreateComment(ctx, owner, string(repo), pullRequestNumber, comment)
 	if err != nil {
 		return err
 	}
@@ -103,13 +120,13 @@ func (g *GitHub) LinkIssueToRepo(owner string, repo RepoConfigName, issueID Issu
 	return nil
 }
 
-func (g *GitHub) StartIssue(owner string, repo RepoConfigName, issueID IssueID) error {
+func (g *GitHub) StartIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error {
 	issueNumber, err := getIssueNumberFromString(issueID)
 	if err != nil {
 		return err
 	}
 
-	issue, _, err := g.client.Issues.Get(context.Background(), owner, string(repo), issueNumber)
+	issue, _, err := g.client.Issues.Get(ctx, owner, string(repo), issueNumber)
 	if err != nil {
 		return err
 	}
@@ -123,7 +140,7 @@ func (g *GitHub) StartIssue(owner string, repo RepoConfigName, issueID IssueID)
 
 	// If not, add the "In Progress" label
 	labels := []string{"In Progress"}
-	_, _, err = g.client.Issues.AddLabelsToIssue(context.Background(), owner, string(repo), issueNumber, labels)
+	_, _, err = g.client.Issues.AddLabelsToIssue(ctx, owner, string(repo), issueNumber, labels)
 	if err != nil {
 		return err
 	}
@@ -137,10 +154,64 @@ func (g *GitHub) StartIssue(owner string, repo RepoConfigName, issueID IssueID)
 
 	// If not, assign the issue to the specified user
 	assignees := []string{g.user}
-	_, _, err = g.client.Issues.AddAssignees(context.Background(), owner, string(repo), issueNumber, assignees)
+	_, _, err = g.client.Issues.AddAssignees(ctx, owner, string(repo), issueNumber, assignees)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (g *GitHub) VerifyCredentials(ctx context.Context) error {
+	_, _, err := g.client.Users.Get(ctx, "")
+	return err
+}
+
+func (g *GitHub) GetPullRequestStatus(ctx context.Context, owner string, repo RepoConfigName, headBranch string) (*PullRequestStatus, error) {
+	pullRequests, _, err := g.client.PullRequests.List(ctx, owner, string(repo), &github.PullRequestListOptions{
+		State: "all",
+		Head:  fmt.Sprintf("%s:%s", owner, headBranch),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pullRequests) == 0 {
+		return nil, nil
+	}
+
+	pr := pullRequests[0]
+	status := &PullRequestStatus{Number: pr.GetNumber(), URL: pr.GetHTMLURL(), State: pr.GetState()}
+	if pr.MergedAt != nil {
+		status.State = "merged"
+	}
+
+	sha := pr.GetHead().GetSHA()
+	if sha == "" {
+		return status, nil
+	}
+	checkRuns, _, err := g.client.Checks.ListCheckRunsForRef(ctx, owner, string(repo), sha, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range checkRuns.CheckRuns {
+		state := run.GetStatus()
+		if state == "completed" {
+			state = run.GetConclusion()
+		}
+		status.Checks = append(status.Checks, CheckStatus{Name: run.GetName(), State: state, URL: run.GetHTMLURL()})
+	}
+
+	// CI services not using checks API report commit statuses
+	combined, _, err := g.client.Repositories.GetCombinedStatus(ctx, owner, string(repo), sha, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, commitStatus := range combined.Statuses {
+		status.Checks = append(status.Checks, CheckStatus{
+			Name:  commitStatus.GetContext(),
+			State: commitStatus.GetState(),
+			URL:   commitStatus.GetTargetURL(),
+		})
+	}
+	return status, nil
+}
@@ -1,6 +1,7 @@
 package issuectl
 
 import (
+	"context"
 	"fmt"
 
 	gitlab "github.com/xanzy/go-gitlab"
@@ -13,17 +14,16 @@ type GitLab struct {
 	baseURL string
 }
 
-func NewGitLabClient(token, baseURL string, userID int) *GitLab {
+func NewGitLabClient(token, baseURL string, userID int) (*GitLab, error) {
 	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
 	if err != nil {
-		Log.Infof("failed to create GitLab client: %v", err)
-		return nil
+		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
 
-	return &GitLab{client: client, userID: userID, token: token, baseURL: baseURL}
+	return &GitLab{client: client, userID: userID, token: token, baseURL: baseURL}, nil
 }
 
-func (g *GitLab) GetIssue(owner string, repo RepoConfigName, issueID IssueID) (interface{}, error) {
+func (g *GitLab) GetIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) (interface{}, error) {
 	Log.Infof("NOT IMPLEMENTED")
 	return nil, nil
 }
@@ -37,7 +37,7 @@ func (g *GitLab) GetIssueURL(owner string, repo RepoConfigName, issueID IssueID)
 	return fmt.Sprintf("https://gitlab.com/%s/%s/-/issues/%d", owner, repo, issueNumber), nil
 }
 
-func (g *GitLab) CloseIssue(owner string, repo RepoConfigName, issueID IssueID) error {
+func (g *GitLab) CloseIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error {
 	issueNumber, err := getIssueNumberFromString(issueID)
 	if err != nil {
 		return err
@@ -47,7 +47,7 @@ func (g *GitLab) CloseIssue(owner string, repo RepoConfigName, issueID IssueID)
 		StateEvent: gitlab.String("close"),
 	}
 
-	_, _, err = g.client.Issues.UpdateIssue(fmt.Sprintf("%s/%s", owner, repo), issueNumber, issueOpt)
+	_, _, err = g.client.Issues.UpdateIssue(fmt.Sprintf("%s/%s", owner, repo), issueNumber, issueOpt, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -55,7 +55,7 @@ func (g *GitLab) CloseIssue(owner string, repo RepoConfigName, issueID IssueID)
 	return nil
 }
 
-func (g *GitLab) OpenPullRequest(owner string, repo RepoConfigName, title, body, baseBranch, headBranch string) (*int, error) {
+func (g *GitLab) OpenPullRequest(ctx context.Context, owner string, repo RepoConfigName, title, body, baseBranch, headBranch string) (*int, error) {
 	pullReqOpt := &gitlab.CreateMergeRequestOptions{
 		Title:        gitlab.String(title),
 		Description:  gitlab.String(body),
@@ -63,15 +63,15 @@ func (g *GitLab) OpenPullRequest(owner string, repo RepoConfigName, title, body,
 		TargetBranch: gitlab.String(baseBranch),
 	}
 
-	mr, _, err := g.client.MergeRequests.CreateMergeRequest(fmt.Sprintf("%s/%s", owner, repo), pullReqOpt)
+	mr, _, err := g.client.MergeRequests.CreateMergeRequest(fmt.Sprintf("%s/%s", owner, repo), pullReqOpt, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
 
-	return &mr.ID, nil
+	return &mr.IID, nil
 }
 
-func (g *GitLab) LinkIssueToRepo(owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error {
+func (g *GitLab) LinkIssueToRepo(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error {
 	issueNumber, err := getIssueNumberFromString(issueID)
 	if err != nil {
 		return err
@@ -88,7 +88,7 @@ func (g *GitLab) LinkIssueToRepo(owner string, repo RepoConfigName, issueID Issu
 		return err
 	}
 
-	_, _, err = g.client.MergeRequests.UpdateMergeRequest(fmt.Sprintf("%s/%s", owner, repo), pullRequestNumber, pullReqOpt)
+	_, _, err = g.client.MergeRequests.UpdateMergeRequest(fmt.Sprintf("%s/%s", owner, repo), pullRequestNumber, pullReqOpt, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -96,13 +96,13 @@ func (g *GitLab) LinkIssueToRepo(owner string, repo RepoConfigName, issueID Issu
 	return nil
 }
 
-func (g *GitLab) StartIssue(owner string, repo RepoConfigName, issueID IssueID) error {
+func (g *GitLab) StartIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error {
 	issueNumber, err := getIssueNumberFromString(issueID)
 	if err != nil {
 		return err
 	}
 
-	issue, _, err := g.client.Issues.GetIssue(fmt.Sprintf("%s/%s", owner, repo), issueNumber, nil)
+	issue, _, err := g.client.Issues.GetIssue(fmt.Sprintf("%s/%s", owner, repo), issueNumber, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -117,10 +117,56 @@ func (g *GitLab) StartIssue(owner string, repo RepoConfigName, issueID IssueID)
 		AssigneeIDs: &[]int{g.userID},
 	}
 
-	_, _, err = g.client.Issues.UpdateIssue(fmt.Sprintf("%s/%s", owner, repo), issueNumber, issueOpt)
+	_, _, err = g.client.Issues.UpdateIssue(fmt.Sprintf("%s/%s", owner, repo), issueNumber, issueOpt, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (g *GitLab) VerifyCredentials(ctx context.Context) error {
+	_, _, err := g.client.Users.CurrentUser(gitlab.WithContext(ctx))
+	return err
+}
+
+func (g *GitLab) GetPullRequestStatus(ctx context.Context, owner string, repo RepoConfigName, headBranch string) (*PullRequestStatus, error) {
+	pid := fmt.Sprintf("%s/%s", owner, repo)
+	mrs, _, err := g.client.MergeRequests.ListProjectMergeRequests(pid, &gitlab.ListProjectMergeRequestsOptions{
+		SourceBranch: gitlab.String(headBranch),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+
+	// merge requests in list don't include pipelines
+	mr, _, err := g.client.MergeRequests.GetMergeRequest(pid, mrs[0].IID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	status := &PullRequestStatus{Number: mr.IID, URL: mr.WebURL, State: mr.State}
+	if mr.State == "opened" {
+		status.State = "open"
+	}
+	pipelineID := 0
+	switch {
+	case mr.HeadPipeline != nil:
+		pipelineID = mr.HeadPipeline.ID
+	case mr.Pipeline != nil:
+		pipelineID = mr.Pipeline.ID
+	default:
+		return status, nil
+	}
+
+	jobs, _, err := g.client.Jobs.ListPipelineJobs(pid, pipelineID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		status.Checks = append(status.Checks, CheckStatus{Name: job.Name, State: job.Status, URL: job.WebURL})
+	}
+	return status, nil
+}
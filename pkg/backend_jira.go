@@ -1,6 +1,7 @@
 package issuectl
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/andygrunwald/go-jira"
@@ -37,29 +38,29 @@ func (j *Jira) GetIssueURL(owner string, repo RepoConfigName, issueID IssueID) (
 	return fmt.Sprintf("%s/browse/%s", j.baseURL, issueID), nil
 }
 
-func (j *Jira) GetIssue(owner string, repo RepoConfigName, issueID IssueID) (interface{}, error) {
-	issue, _, err := j.client.Issue.Get(string(issueID), nil)
+func (j *Jira) GetIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) (interface{}, error) {
+	issue, _, err := j.client.Issue.GetWithContext(ctx, string(issueID), nil)
 	if err != nil {
 		return nil, err
 	}
 	return issue, nil
 }
 
-func (j *Jira) StartIssue(owner string, repo RepoConfigName, issueID IssueID) error {
-	return j.moveIssueToState(issueID, InProgress, DefaultStartMessage)
+func (j *Jira) StartIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error {
+	return j.moveIssueToState(ctx, issueID, InProgress, DefaultStartMessage)
 }
 
-func (j *Jira) CloseIssue(owner string, repo RepoConfigName, issueID IssueID) error {
-	return j.moveIssueToState(issueID, Done, DefaultCloseMessage)
+func (j *Jira) CloseIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error {
+	return j.moveIssueToState(ctx, issueID, Done, DefaultCloseMessage)
 }
 
-func (j *Jira) LinkIssueToRepo(owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error {
+func (j *Jira) LinkIssueToRepo(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error {
 	pullRequestURL := fmt.Sprintf("https://github.com/%s/%s/pull/%s", owner, repo, pullRequestID)
 
 	comment := jira.Comment{
 		Body: fmt.Sprintf(DefaultOpenPRMessage, pullRequestURL),
 	}
-	_, _, err := j.client.Issue.AddComment(string(issueID), &comment)
+	_, _, err := j.client.Issue.AddCommentWithContext(ctx, string(issueID), &comment)
 	if err != nil {
 		return err
 	}
@@ -67,8 +68,8 @@ func (j *Jira) LinkIssueToRepo(owner string, repo RepoConfigName, issueID IssueI
 	return nil
 }
 
-func (j *Jira) moveIssueToState(issueID IssueID, desiredState string, message string) error {
-	issue, _, err := j.client.Issue.Get(string(issueID), nil)
+func (j *Jira) moveIssueToState(ctx context.Context, issueID IssueID, desiredState string, message string) error {
+	issue, _, err := j.client.Issue.GetWithContext(ctx, string(issueID), nil)
 	if err != nil {
 		return err
 	}
@@ -78,7 +79,7 @@ func (j *Jira) moveIssueToState(issueID IssueID, desiredState string, message st
 		return nil
 	}
 
-	transitions, _, err := j.client.Issue.GetTransitions(string(issueID))
+	transitions, _, err := j.client.Issue.GetTransitionsWithContext(ctx, string(issueID))
 	if err != nil {
 		return err
 	}
@@ -97,7 +98,7 @@ func (j *Jira) moveIssueToState(issueID IssueID, desiredState string, message st
 		return fmt.Errorf("unable to find '%s' transition", desiredState)
 	}
 
-	_, err = j.client.Issue.DoTransition(string(issueID), transitionID)
+	_, err = j.client.Issue.DoTransitionWithContext(ctx, string(issueID), transitionID)
 	if err != nil {
 		return err
 	}
@@ -106,7 +107,7 @@ func (j *Jira) moveIssueToState(issueID IssueID, desiredState string, message st
 		comment := jira.Comment{
 			Body: message,
 		}
-		_, _, err = j.client.Issue.AddComment(string(issueID), &comment)
+		_, _, err = j.client.Issue.AddCommentWithContext(ctx, string(issueID), &comment)
 		if err != nil {
 			return err
 		}
@@ -114,3 +115,8 @@ func (j *Jira) moveIssueToState(issueID IssueID, desiredState string, message st
 
 	return nil
 }
+
+func (j *Jira) VerifyCredentials(ctx context.Context) error {
+	_, _, err := j.client.User.GetSelfWithContext(ctx)
+	return err
+}
@@ -1,7 +1,7 @@
 package issuectl
 
 import (
-	"encoding/base64"
+	"context"
 	"fmt"
 	"strconv"
 )
@@ -23,51 +23,75 @@ func getIssueNumberFromString(issueID IssueID) (int, error) {
 }
 
 type IssueBackend interface {
-	LinkIssueToRepo(owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error
-	CloseIssue(owner string, repo RepoConfigName, issueID IssueID) error
-	StartIssue(owner string, repo RepoConfigName, issueID IssueID) error
-	GetIssue(owner string, repo RepoConfigName, issueID IssueID) (interface{}, error)
+	LinkIssueToRepo(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID, pullRequestID string) error
+	CloseIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error
+	StartIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) error
+	GetIssue(ctx context.Context, owner string, repo RepoConfigName, issueID IssueID) (interface{}, error)
 	GetIssueURL(owner string, repo RepoConfigName, issueID IssueID) (string, error)
 }
 
+// CredentialsVerifier checks if credentials configured for backend are accepted by its API
+type CredentialsVerifier interface {
+	VerifyCredentials(ctx context.Context) error
+}
+
 type RepositoryBackend interface {
-	OpenPullRequest(owner string, repo RepoConfigName, title, body, baseBranch, headBranch string) (*int, error)
+	OpenPullRequest(ctx context.Context, owner string, repo RepoConfigName, title, body, baseBranch, headBranch string) (*int, error)
+}
+
+// VerifyBackendCredentials calls backend API to check if configured credentials work
+func VerifyBackendCredentials(ctx context.Context, backendConfig *BackendConfig) error {
+	backend, err := getIssueBackendConfigurator(ctx, backendConfig)
+	if err != nil {
+		return err
+	}
+	verifier, ok := backend.(CredentialsVerifier)
+	if !ok {
+		return fmt.Errorf("backend %v doesn't support verifying credentials", backendConfig.Type)
+	}
+	return verifier.VerifyCredentials(ctx)
 }
 
 // getIssueBackendConfigurator prepares IssueBackend
-func getIssueBackendConfigurator(backendConfig *BackendConfig) (IssueBackend, error) {
+func getIssueBackendConfigurator(ctx context.Context, backendConfig *BackendConfig) (IssueBackend, error) {
+	if backendConfig == nil {
+		return nil, fmt.Errorf("backend not defined")
+	}
+	if problems := validateBackend(backendConfig); len(problems) > 0 {
+		return nil, newValidationError(problems)
+	}
+	token, err := ResolveBackendToken(ctx, backendConfig)
+	if err != nil {
+		return nil, err
+	}
 	switch backendConfig.Type {
 
 	case BackendGithub:
-		token, err := base64.RawStdEncoding.DecodeString(backendConfig.GitHub.Token)
-		if err != nil {
-			return nil, err
-		}
-		return NewGitHubClient(
-			string(token),
+		client, err := NewGitHubClient(
+			token,
 			backendConfig.GitHub.Host,
 			backendConfig.GitHub.Username,
-		), nil
-
-	case BackendGitLab:
-		token, err := base64.RawStdEncoding.DecodeString(backendConfig.GitLab.Token)
+		)
 		if err != nil {
 			return nil, err
 		}
-		return NewGitLabClient(
-			string(token),
+		return client, nil
+
+	case BackendGitLab:
+		client, err := NewGitLabClient(
+			token,
 			backendConfig.GitLab.Host,
 			backendConfig.GitLab.UserID,
-		), nil
-
-	case BackendJira:
-		token, err := base64.RawStdEncoding.DecodeString(backendConfig.Jira.Token)
+		)
 		if err != nil {
 			return nil, err
 		}
+		return client, nil
+
+	case BackendJira:
 		return NewJiraClient(
 			backendConfig.Jira.Username,
-			string(token),
+			token,
 			backendConfig.Jira.Host,
 		), nil
 	default:
@@ -76,29 +100,39 @@ func getIssueBackendConfigurator(backendConfig *BackendConfig) (IssueBackend, er
 }
 
 // getRepoBackendConfigurator prepares RepositoryBackend
-func getRepoBackendConfigurator(backendConfig *BackendConfig) (RepositoryBackend, error) {
+func getRepoBackendConfigurator(ctx context.Context, backendConfig *BackendConfig) (RepositoryBackend, error) {
+	if backendConfig == nil {
+		return nil, fmt.Errorf("backend not defined")
+	}
+	if problems := validateBackend(backendConfig); len(problems) > 0 {
+		return nil, newValidationError(problems)
+	}
+	token, err := ResolveBackendToken(ctx, backendConfig)
+	if err != nil {
+		return nil, err
+	}
 	switch backendConfig.Type {
 	case BackendGithub:
-		token, err := base64.RawStdEncoding.DecodeString(backendConfig.GitHub.Token)
+		client, err := NewGitHubClient(
+			token,
+			backendConfig.GitHub.Host,
+			backendConfig.GitHub.Username,
+		)
 		if err != nil {
 			return nil, err
 		}
-		return NewGitHubClient(
-			string(token),
-			backendConfig.GitHub.Host,
-			backendConfig.GitHub.Username,
-		), nil
+		return client, nil
 
 	case BackendGitLab:
-		token, err := base64.RawStdEncoding.DecodeString(backendConfig.GitLab.Token)
+		client, err := NewGitLabClient(
+			token,
+			backendConfig.GitLab.Host,
+			backendConfig.GitLab.UserID,
+		)
 		if err != nil {
 			return nil, err
 		}
-		return NewGitLabClient(
-			string(token),
-			backendConfig.GitLab.Host,
-			backendConfig.GitLab.UserID,
-		), nil
+		return client, nil
 	default:
 		return nil, fmt.Errorf("Backend %v not supported", backendConfig.Type)
 	}
@@ -0,0 +1,360 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// BundleVersion is version of bundle format written by ExportBundle
+	BundleVersion = 1
+	// DefaultBundleFileName is name of bundle file looked up in git repositories
+	DefaultBundleFileName = "issuectl-bundle.yaml"
+)
+
+// Bundle is a shareable part of config: repositories, profiles and backends without secrets.
+// Git users are personal and never exported.
+type Bundle struct {
+	Version      int                                  `yaml:"version"`
+	Repositories map[RepoConfigName]*RepoConfig       `yaml:"repositories,omitempty"`
+	Profiles     map[ProfileName]*Profile             `yaml:"profiles,omitempty"`
+	Backends     map[BackendConfigName]*BackendConfig `yaml:"backends,omitempty"`
+}
+
+// ExportBundle exports given profiles, with profiles they extend and repositories and backends they use.
+// All profiles are exported when none is given.
+func ExportBundle(config IssuectlConfig, profileNames ...ProfileName) (*Bundle, error) {
+	ic, ok := config.(*issuectlConfig)
+	if !ok {
+		return nil, fmt.Errorf("unsupported config implementation %T", config)
+	}
+	source, err := ic.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		Version:      BundleVersion,
+		Repositories: map[RepoConfigName]*RepoConfig{},
+		Profiles:     map[ProfileName]*Profile{},
+		Backends:     map[BackendConfigName]*BackendConfig{},
+	}
+
+	if len(profileNames) == 0 {
+		profileNames = SortedKeys(source.Profiles)
+		for _, repo := range source.Repositories {
+			bundle.Repositories[repo.Name] = repo
+		}
+		for _, backend := range source.Backends {
+			bundle.Backends[backend.Name] = backend
+		}
+	}
+
+	for _, name := range profileNames {
+		chain, err := profileChain(source.Profiles, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, profile := range chain {
+			profile.GitUserName = ""
+			bundle.Profiles[profile.Name] = profile
+			for _, repoName := range append([]RepoConfigName{profile.DefaultRepository}, profile.Repositories...) {
+				if repo := source.Repositories[repoName]; repo != nil {
+					bundle.Repositories[repoName] = repo
+				}
+			}
+			for _, backendName := range []BackendConfigName{profile.IssueBackend, profile.RepoBackend} {
+				if backend := source.Backends[backendName]; backend != nil {
+					bundle.Backends[backendName] = backend
+				}
+			}
+		}
+	}
+
+	for _, backend := range bundle.Backends {
+		stripSecrets(backend)
+	}
+	return bundle, nil
+}
+
+// stripSecrets removes tokens and references to local vault from backend
+func stripSecrets(backend *BackendConfig) {
+	backend.ClearStoredToken()
+	if backend.TokenSource != nil && backend.TokenSource.Vault != "" {
+		backend.TokenSource = nil
+	}
+}
+
+// LoadBundle reads bundle from file or, when source is git repository URL, from file in that repository
+// cloned with git client of current profile
+func LoadBundle(ctx context.Context, config IssuectlConfig, source, file string) (*Bundle, error) {
+	path := source
+	if isGitURL(source) {
+		dir, err := os.MkdirTemp("", "issuectl-bundle-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := currentGitClient(config).Clone(ctx, RepoURL(source), dir, CloneOptions{Depth: 1}); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to clone %v: %w", source, err)
+		}
+		if file == "" {
+			file = DefaultBundleFileName
+		}
+		path = filepath.Join(dir, file)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	bundle := &Bundle{}
+	if err := unmarshalConfigStrict(source, data, bundle); err != nil {
+		return nil, err
+	}
+	if bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %v is newer than supported %v, please upgrade issuectl", bundle.Version, BundleVersion)
+	}
+	return bundle, nil
+}
+
+func isGitURL(source string) bool {
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@") || strings.HasSuffix(source, ".git")
+}
+
+// ImportOptions control how bundle is merged into config
+type ImportOptions struct {
+	// DryRun reports changes without applying them
+	DryRun bool
+	// Overwrite replaces existing entities which differ from ones in bundle
+	Overwrite bool
+	// GitUser is set on imported profiles, defaults to the only git user defined in config
+	GitUser GitUserName
+	// Force saves config even when import introduces problems found by validation
+	Force bool
+	// AllowHooks accepts hooks from bundle, they run arbitrary commands
+	AllowHooks bool
+}
+
+// ImportResult lists what happened to each entity from bundle, entries are formatted as "<kind> <name>"
+type ImportResult struct {
+	Added     []string
+	Updated   []string
+	Unchanged []string
+	Conflicts []string
+	Problems  []string
+	// Hooks lists commands of hooks which import adds or changes, formatted as "<kind> <name> <event>: <command>"
+	Hooks       []string
+	DryRun      bool
+	Overwritten bool
+}
+
+// ImportBundle merges bundle into config. Entities which exist in config and differ from ones in bundle are
+// conflicts, kept unchanged unless opts.Overwrite is set. Locally configured backend secrets are kept.
+// Config isn't saved when import introduces validation problems, unless opts.Force is set, or when it adds hooks,
+// unless opts.AllowHooks is set. Returned result describes import also when it's refused.
+func ImportBundle(config IssuectlConfig, bundle *Bundle, opts ImportOptions) (*ImportResult, error) {
+	ic, ok := config.(*issuectlConfig)
+	if !ok {
+		return nil, fmt.Errorf("unsupported config implementation %T", config)
+	}
+	updated, err := ic.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	gitUser := opts.GitUser
+	if gitUser == "" && len(updated.GitUsers) == 1 {
+		gitUser = SortedKeys(updated.GitUsers)[0]
+	}
+	if gitUser != "" && updated.GitUsers[gitUser] == nil {
+		return nil, fmt.Errorf("git user %v not defined", gitUser)
+	}
+
+	result := &ImportResult{DryRun: opts.DryRun, Overwritten: opts.Overwrite}
+	merge := func(kind, name string, existing, imported interface{}, store func()) {
+		entry := fmt.Sprintf("%v %v", kind, name)
+		switch {
+		case existing == nil:
+			result.Added = append(result.Added, entry)
+			store()
+		case sameValue(existing, imported):
+			result.Unchanged = append(result.Unchanged, entry)
+		case opts.Overwrite:
+			result.Updated = append(result.Updated, entry)
+			store()
+		default:
+			result.Conflicts = append(result.Conflicts, entry)
+		}
+	}
+
+	for _, name := range SortedKeys(bundle.Repositories) {
+		repo := bundle.Repositories[name]
+		repo.Name = name
+		existing := updated.Repositories[name]
+		merge("repository", string(name), nilIfEmpty(existing), repo, func() {
+			result.Hooks = append(result.Hooks, changedHooks("repository", string(name), repoHooks(existing), repo.Hooks)...)
+			if repo.AllowProjectHooks && (existing == nil || !existing.AllowProjectHooks) {
+				result.Hooks = append(result.Hooks, fmt.Sprintf("repository %v: hooks from its %v", name, ProjectConfigFileName))
+			}
+			updated.Repositories[name] = repo
+		})
+	}
+
+	for _, name := range SortedKeys(bundle.Backends) {
+		backend := bundle.Backends[name]
+		backend.Name = name
+		stripSecrets(backend)
+		existing := updated.Backends[name]
+		var comparable *BackendConfig
+		if existing != nil {
+			// secrets are never part of bundle so they aren't compared and local ones are kept
+			comparable = withoutSecrets(existing)
+			keepLocalSecrets(backend, existing)
+		}
+		merge("backend", string(name), nilIfEmpty(comparable), withoutSecrets(backend), func() {
+			updated.Backends[name] = backend
+		})
+	}
+
+	for _, name := range SortedKeys(bundle.Profiles) {
+		profile := bundle.Profiles[name]
+		profile.Name = name
+		existing := updated.Profiles[name]
+		if existing != nil && profile.GitUserName == "" {
+			// git user is personal, keep the one set locally
+			profile.GitUserName = existing.GitUserName
+		}
+		if profile.GitUserName == "" {
+			profile.GitUserName = gitUser
+		}
+		merge("profile", string(name), nilIfEmpty(existing), profile, func() {
+			result.Hooks = append(result.Hooks, changedHooks("profile", string(name), profileHooks(existing), profile.Hooks)...)
+			updated.Profiles[name] = profile
+		})
+	}
+
+	result.Problems = newProblems(ic.Validate(), updated.Validate())
+	if opts.DryRun {
+		return result, nil
+	}
+	if len(result.Problems) > 0 && !opts.Force {
+		return result, fmt.Errorf("import would introduce %v config problems, config not changed, use --force to import anyway", len(result.Problems))
+	}
+	if len(result.Hooks) > 0 && !opts.AllowHooks {
+		return result, fmt.Errorf("bundle adds %v hooks running commands, config not changed, review them and use --allow-hooks", len(result.Hooks))
+	}
+	ic.replaceWith(updated)
+	if err := ic.Save(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func repoHooks(repo *RepoConfig) Hooks {
+	if repo == nil {
+		return nil
+	}
+	return repo.Hooks
+}
+
+func profileHooks(profile *Profile) Hooks {
+	if profile == nil {
+		return nil
+	}
+	return profile.Hooks
+}
+
+// changedHooks lists hooks of imported entity when they differ from existing ones
+func changedHooks(kind, name string, existing, imported Hooks) []string {
+	if len(imported) == 0 || sameValue(existing, imported) {
+		return nil
+	}
+	hooks := []string{}
+	for _, event := range SortedKeys(imported) {
+		for _, hook := range imported[event] {
+			hooks = append(hooks, fmt.Sprintf("%v %v %v: %v", kind, name, event, hook.Run))
+		}
+	}
+	return hooks
+}
+
+// copyBackendSettings returns copies of backend type specific settings so that stripping secrets doesn't affect original
+func copyBackendSettings(backend *BackendConfig) (*GitHubConfig, *GitLabConfig, *JiraConfig) {
+	var github *GitHubConfig
+	var gitlab *GitLabConfig
+	var jira *JiraConfig
+	if backend.GitHub != nil {
+		copied := *backend.GitHub
+		github = &copied
+	}
+	if backend.GitLab != nil {
+		copied := *backend.GitLab
+		gitlab = &copied
+	}
+	if backend.Jira != nil {
+		copied := *backend.Jira
+		jira = &copied
+	}
+	return github, gitlab, jira
+}
+
+// keepLocalSecrets copies token and token source configured locally to imported backend of the same type
+func keepLocalSecrets(imported, existing *BackendConfig) {
+	if imported.Type != existing.Type {
+		return
+	}
+	if imported.TokenSource == nil {
+		imported.TokenSource = existing.TokenSource
+	}
+	token := existing.getStoredToken()
+	switch {
+	case imported.GitHub != nil:
+		imported.GitHub.Token = token
+	case imported.GitLab != nil:
+		imported.GitLab.Token = token
+	case imported.Jira != nil:
+		imported.Jira.Token = token
+	}
+}
+
+// withoutSecrets returns copy of backend without secrets, used for comparing with bundle
+func withoutSecrets(backend *BackendConfig) *BackendConfig {
+	copied := *backend
+	copied.GitHub, copied.GitLab, copied.Jira = copyBackendSettings(backend)
+	stripSecrets(&copied)
+	return &copied
+}
+
+// nilIfEmpty turns typed nil pointer into untyped nil
+func nilIfEmpty[T any](value *T) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value
+}
+
+// Summary returns sorted list of import result entries prefixed with what happened to them
+func (r *ImportResult) Summary() []string {
+	lines := []string{}
+	add := func(prefix string, entries []string) {
+		sorted := append([]string{}, entries...)
+		sort.Strings(sorted)
+		for _, entry := range sorted {
+			lines = append(lines, fmt.Sprintf("%v %v", prefix, entry))
+		}
+	}
+	add("added", r.Added)
+	add("updated", r.Updated)
+	add("unchanged", r.Unchanged)
+	add("conflict", r.Conflicts)
+	return lines
+}
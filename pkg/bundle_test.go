@@ -0,0 +1,204 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func bundleSourceConfig() IssuectlConfig {
+	config := crudConfig()
+	config.GetBackend("gh").GitHub.Token = EncodeToken("secret")
+	if err := config.AddProfile(&Profile{Name: "frontend", Extends: "work", Repositories: []RepoConfigName{"unused"}}); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// TestExportBundle tests that bundle contains selected profiles with dependencies and no secrets.
+func TestExportBundle(t *testing.T) {
+	config := bundleSourceConfig()
+	// settings block not matching backend type, e.g. left behind by hand edit
+	config.GetBackend("gh").GitLab = &GitLabConfig{Token: EncodeToken("stale")}
+	bundle, err := ExportBundle(config, "frontend")
+	if err != nil {
+		t.Fatalf("ExportBundle() failed: %s", err)
+	}
+
+	if profiles := SortedKeys(bundle.Profiles); !reflect.DeepEqual(profiles, []ProfileName{"frontend", "work"}) {
+		t.Errorf("expected profile with its parent, got %v", profiles)
+	}
+	if repos := SortedKeys(bundle.Repositories); !reflect.DeepEqual(repos, []RepoConfigName{"api", "unused"}) {
+		t.Errorf("expected repositories used by profiles, got %v", repos)
+	}
+	if bundle.Backends["gh"].GitHub.Token != "" || bundle.Backends["gh"].GitLab.Token != "" {
+		t.Errorf("token exported in bundle")
+	}
+	if bundle.Profiles["work"].GitUserName != "" {
+		t.Errorf("git user exported in bundle")
+	}
+
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("failed to marshal bundle: %s", err)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write bundle: %s", err)
+	}
+	if _, err := LoadBundle(context.Background(), GetEmptyConfig().GetInMemory(), path, ""); err != nil {
+		t.Errorf("LoadBundle() of exported bundle failed: %s", err)
+	}
+}
+
+// TestImportBundle tests merging bundle into config with conflicts, overwrite and dry run.
+func TestImportBundle(t *testing.T) {
+	bundle, err := ExportBundle(bundleSourceConfig())
+	if err != nil {
+		t.Fatalf("ExportBundle() failed: %s", err)
+	}
+	bundle.Repositories["api"].Owner = "other-org"
+
+	config := GetConfig("", map[RepoConfigName]*RepoConfig{
+		"api": {Name: "api", Owner: "org", RepoURL: "git@example.com:org/api.git"},
+	}, map[BackendConfigName]*BackendConfig{
+		"gh": {Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{Username: "tester", Token: EncodeToken("mine")}},
+	}, map[GitUserName]*GitUser{
+		"me": {Name: "me", Email: "me@example.com", SSHKey: "/dev/null"},
+	}, nil).GetInMemory()
+
+	result, err := ImportBundle(config, bundle, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportBundle() failed: %s", err)
+	}
+	if len(config.GetProfiles()) != 0 {
+		t.Errorf("dry run changed config")
+	}
+	expected := []string{
+		"added profile frontend",
+		"added profile work",
+		"added repository unused",
+		"unchanged backend gh",
+		"conflict repository api",
+	}
+	if summary := result.Summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected summary %v, got %v", expected, summary)
+	}
+
+	if _, err := ImportBundle(config, bundle, ImportOptions{}); err != nil {
+		t.Fatalf("ImportBundle() failed: %s", err)
+	}
+	if owner := config.GetRepository("api").Owner; owner != "org" {
+		t.Errorf("conflicting repository overwritten without --overwrite, owner %v", owner)
+	}
+	if gitUser := config.GetProfile("work").GitUserName; gitUser != "me" {
+		t.Errorf("expected imported profile to use the only git user, got %v", gitUser)
+	}
+
+	if _, err := ImportBundle(config, bundle, ImportOptions{Overwrite: true}); err != nil {
+		t.Fatalf("ImportBundle() failed: %s", err)
+	}
+	if owner := config.GetRepository("api").Owner; owner != "other-org" {
+		t.Errorf("expected repository to be overwritten, owner %v", owner)
+	}
+	if token := config.GetBackend("gh").GitHub.Token; token != EncodeToken("mine") {
+		t.Errorf("local token not kept, got %v", token)
+	}
+}
+
+// TestLoadBundleFromGit tests reading bundle committed to git repository.
+func TestLoadBundleFromGit(t *testing.T) {
+	fixture := newGitFixture(t, "team-config")
+	work := filepath.Join(t.TempDir(), "work")
+	runGitFixture(t, filepath.Dir(work), "clone", fixture.URL, work)
+	bundle := "version: 1\nrepositories:\n  api:\n    name: api\n    owner: org\n    url: git@example.com:org/api.git\n"
+	if err := os.WriteFile(filepath.Join(work, DefaultBundleFileName), []byte(bundle), 0644); err != nil {
+		t.Fatalf("failed to write bundle: %s", err)
+	}
+	runGitFixture(t, work, "add", DefaultBundleFileName)
+	runGitFixture(t, work, "commit", "-m", "Add bundle")
+	runGitFixture(t, work, "push", "origin", "HEAD:master")
+
+	for _, name := range gitClients {
+		t.Run(name, func(t *testing.T) {
+			env := newTestEnv(t, nil, nil, "service")
+			env.setGitClient(t, name)
+
+			loaded, err := LoadBundle(context.Background(), env.config, "file://"+fixture.URL, "")
+			if err != nil {
+				t.Fatalf("LoadBundle() failed: %s", err)
+			}
+			if loaded.Repositories["api"] == nil {
+				t.Errorf("expected repository api in bundle, got %+v", loaded)
+			}
+		})
+	}
+}
+
+// TestImportBundleSafety tests that import adding hooks or problems isn't saved without explicit consent.
+func TestImportBundleSafety(t *testing.T) {
+	newConfig := func() IssuectlConfig {
+		return GetConfig("", nil, map[BackendConfigName]*BackendConfig{
+			"tracker": {Name: "tracker", Type: BackendJira, Jira: &JiraConfig{Host: "https://jira.example.com", Token: EncodeToken("mine")}},
+		}, nil, nil).GetInMemory()
+	}
+	hooksBundle := &Bundle{Version: BundleVersion, Repositories: map[RepoConfigName]*RepoConfig{
+		"api": {Owner: "org", RepoURL: "git@example.com:org/api.git", Hooks: Hooks{HookPostStart: {{Run: "make setup"}}}},
+	}}
+
+	config := newConfig()
+	result, err := ImportBundle(config, hooksBundle, ImportOptions{})
+	if err == nil || !strings.Contains(err.Error(), "--allow-hooks") {
+		t.Fatalf("expected import with hooks to be refused, got %v", err)
+	}
+	if expected := []string{"repository api postStart: make setup"}; !reflect.DeepEqual(result.Hooks, expected) {
+		t.Errorf("expected hooks %v, got %v", expected, result.Hooks)
+	}
+	if config.GetRepository("api") != nil {
+		t.Errorf("refused import changed config")
+	}
+	if _, err := ImportBundle(config, hooksBundle, ImportOptions{AllowHooks: true}); err != nil {
+		t.Fatalf("ImportBundle() with allowed hooks failed: %s", err)
+	}
+
+	projectHooksBundle := &Bundle{Version: BundleVersion, Repositories: map[RepoConfigName]*RepoConfig{
+		"web": {Owner: "org", RepoURL: "git@example.com:org/web.git", AllowProjectHooks: true},
+	}}
+	result, err = ImportBundle(config, projectHooksBundle, ImportOptions{})
+	if err == nil || !strings.Contains(err.Error(), "--allow-hooks") {
+		t.Fatalf("expected import allowing project hooks to be refused, got %v", err)
+	}
+	if expected := []string{"repository web: hooks from its .issuectl.yaml"}; !reflect.DeepEqual(result.Hooks, expected) {
+		t.Errorf("expected hooks %v, got %v", expected, result.Hooks)
+	}
+
+	invalidBundle := &Bundle{Version: BundleVersion, Profiles: map[ProfileName]*Profile{
+		"work": {WorkDir: "/work", Repositories: []RepoConfigName{"missing"}},
+	}}
+	config = newConfig()
+	if _, err := ImportBundle(config, invalidBundle, ImportOptions{}); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected import introducing problems to be refused, got %v", err)
+	}
+	if config.GetProfile("work") != nil {
+		t.Errorf("refused import changed config")
+	}
+	if _, err := ImportBundle(config, invalidBundle, ImportOptions{Force: true}); err != nil {
+		t.Fatalf("ImportBundle() with force failed: %s", err)
+	}
+
+	typeBundle := &Bundle{Version: BundleVersion, Backends: map[BackendConfigName]*BackendConfig{
+		"tracker": {Type: BackendGithub, GitHub: &GitHubConfig{Username: "tester"}},
+	}}
+	config = newConfig()
+	if _, err := ImportBundle(config, typeBundle, ImportOptions{Overwrite: true}); err != nil {
+		t.Fatalf("ImportBundle() failed: %s", err)
+	}
+	if token := config.GetBackend("tracker").GitHub.Token; token != "" {
+		t.Errorf("token of jira backend kept for github backend")
+	}
+}
@@ -0,0 +1,189 @@
+package issuectl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BackendCapability is a feature that backend can provide
+type BackendCapability string
+
+const (
+	// CapabilityIssueTracking allows moving issues to In Progress and Done
+	CapabilityIssueTracking BackendCapability = "issue tracking"
+	// CapabilityIssueDetails allows fetching issue details like title
+	CapabilityIssueDetails BackendCapability = "issue details"
+	// CapabilityPullRequests allows opening pull requests
+	CapabilityPullRequests BackendCapability = "pull requests"
+	// CapabilityLinkPullRequests allows linking pull requests to issues
+	CapabilityLinkPullRequests BackendCapability = "linking pull requests"
+)
+
+var backendCapabilities = map[BackendType][]BackendCapability{
+	BackendGithub: {
+		CapabilityIssueTracking,
+		CapabilityIssueDetails,
+		CapabilityPullRequests,
+		CapabilityLinkPullRequests,
+	},
+	BackendGitLab: {
+		CapabilityIssueTracking,
+		CapabilityPullRequests,
+		CapabilityLinkPullRequests,
+	},
+	BackendJira: {
+		CapabilityIssueTracking,
+		CapabilityIssueDetails,
+		CapabilityLinkPullRequests,
+	},
+}
+
+// Capabilities returns list of capabilities supported by BackendType
+func (t BackendType) Capabilities() []BackendCapability {
+	return backendCapabilities[t]
+}
+
+// Supports checks if BackendType provides given capability
+func (t BackendType) Supports(capability BackendCapability) bool {
+	for _, c := range t.Capabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// BackendRole describes what profile uses backend for
+type BackendRole string
+
+const (
+	RoleIssueBackend BackendRole = "issue backend"
+	RoleRepoBackend  BackendRole = "repository backend"
+)
+
+// Operation is a command which relies on backends
+type Operation string
+
+const (
+	OperationStart  Operation = "start"
+	OperationOpenPR Operation = "openpr"
+	OperationFinish Operation = "finish"
+)
+
+// capabilityRequirement describes capability operation needs from backend in given role
+type capabilityRequirement struct {
+	role       BackendRole
+	capability BackendCapability
+	// optional requirements don't fail the operation, step using them is skipped
+	optional bool
+	// backendRequired fails the operation when no backend is set for the role
+	backendRequired bool
+}
+
+var operationRequirements = map[Operation][]capabilityRequirement{
+	OperationStart: {
+		{role: RoleIssueBackend, capability: CapabilityIssueTracking},
+		{role: RoleIssueBackend, capability: CapabilityIssueDetails, optional: true},
+	},
+	OperationOpenPR: {
+		{role: RoleRepoBackend, capability: CapabilityPullRequests, backendRequired: true},
+		{role: RoleIssueBackend, capability: CapabilityLinkPullRequests, optional: true},
+	},
+	OperationFinish: {
+		{role: RoleIssueBackend, capability: CapabilityIssueTracking},
+	},
+}
+
+// OperationSupport holds capabilities available for operation with given profile
+type OperationSupport struct {
+	available map[BackendCapability]bool
+}
+
+// Has checks if capability can be used during operation
+func (s *OperationSupport) Has(capability BackendCapability) bool {
+	return s.available[capability]
+}
+
+// profileBackendName returns name of the backend profile uses in given role
+func profileBackendName(profile *Profile, role BackendRole) BackendConfigName {
+	if role == RoleRepoBackend {
+		return profile.RepoBackend
+	}
+	return profile.IssueBackend
+}
+
+// CheckOperationSupport validates that backends from profile support everything operation needs.
+// Missing optional capabilities are not an error, they are reported as unavailable in OperationSupport.
+func CheckOperationSupport(config IssuectlConfig, profile *Profile, operation Operation) (*OperationSupport, error) {
+	support := &OperationSupport{available: map[BackendCapability]bool{}}
+	for _, req := range operationRequirements[operation] {
+		backendName := profileBackendName(profile, req.role)
+		if backendName == "" {
+			if req.backendRequired {
+				return nil, fmt.Errorf(
+					"%v requires %v but profile %v doesn't define one",
+					operation, req.role, profile.Name,
+				)
+			}
+			continue
+		}
+
+		backend := config.GetBackend(backendName)
+		if backend == nil {
+			return nil, fmt.Errorf(
+				"%v %v used by profile %v is not defined",
+				req.role, backendName, profile.Name,
+			)
+		}
+
+		if !backend.Type.Supports(req.capability) {
+			if req.optional {
+				Log.V(2).Infof("%v %v (%v) doesn't support %v, skipping", req.role, backendName, backend.Type, req.capability)
+				continue
+			}
+			return nil, fmt.Errorf(
+				"%v can't run with profile %v: %v %v (%v) doesn't support %v (supported: %v)",
+				operation, profile.Name, req.role, backendName, backend.Type,
+				req.capability, formatCapabilities(backend.Type.Capabilities()),
+			)
+		}
+		support.available[req.capability] = true
+	}
+	return support, nil
+}
+
+// ValidateProfileBackends checks that backends used by profile exist and can fulfil their roles
+func ValidateProfileBackends(config IssuectlConfig, profile *Profile) error {
+	roles := map[BackendRole]BackendCapability{
+		RoleIssueBackend: CapabilityIssueTracking,
+		RoleRepoBackend:  CapabilityPullRequests,
+	}
+	for _, role := range []BackendRole{RoleIssueBackend, RoleRepoBackend} {
+		backendName := profileBackendName(profile, role)
+		if backendName == "" {
+			continue
+		}
+		backend := config.GetBackend(backendName)
+		if backend == nil {
+			return fmt.Errorf("%v %v is not defined", role, backendName)
+		}
+		if !backend.Type.Supports(roles[role]) {
+			return fmt.Errorf(
+				"backend %v (%v) can't be used as %v - it doesn't support %v (supported: %v)",
+				backendName, backend.Type, role, roles[role], formatCapabilities(backend.Type.Capabilities()),
+			)
+		}
+	}
+	return nil
+}
+
+func formatCapabilities(capabilities []BackendCapability) string {
+	if len(capabilities) == 0 {
+		return "none"
+	}
+	names := []string{}
+	for _, c := range capabilities {
+		names = append(names, string(c))
+	}
+	return strings.Join(names, ", ")
+}
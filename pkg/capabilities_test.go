@@ -0,0 +1,112 @@
+package issuectl
+
+import (
+	"testing"
+)
+
+func getCapabilitiesTestConfig(t *testing.T) IssuectlConfig {
+	config := GetEmptyConfig().GetInMemory()
+	for name, backendType := range map[BackendConfigName]BackendType{
+		"gh":   BackendGithub,
+		"gl":   BackendGitLab,
+		"jira": BackendJira,
+	} {
+		if err := config.AddBackend(&BackendConfig{Name: name, Type: backendType}); err != nil {
+			t.Fatalf("AddBackend() failed: %s", err)
+		}
+	}
+	return config
+}
+
+// TestCheckOperationSupport tests the CheckOperationSupport function.
+func TestCheckOperationSupport(t *testing.T) {
+	config := getCapabilitiesTestConfig(t)
+
+	tests := []struct {
+		name         string
+		profile      *Profile
+		operation    Operation
+		wantErr      bool
+		available    []BackendCapability
+		notAvailable []BackendCapability
+	}{
+		{
+			name:      "openpr with jira as repo backend",
+			profile:   &Profile{Name: "p", IssueBackend: "jira", RepoBackend: "jira"},
+			operation: OperationOpenPR,
+			wantErr:   true,
+		},
+		{
+			name:      "openpr without repo backend",
+			profile:   &Profile{Name: "p", IssueBackend: "jira"},
+			operation: OperationOpenPR,
+			wantErr:   true,
+		},
+		{
+			name:         "openpr without issue backend skips linking",
+			profile:      &Profile{Name: "p", RepoBackend: "gh"},
+			operation:    OperationOpenPR,
+			available:    []BackendCapability{CapabilityPullRequests},
+			notAvailable: []BackendCapability{CapabilityLinkPullRequests},
+		},
+		{
+			name:         "start with gitlab skips issue details",
+			profile:      &Profile{Name: "p", IssueBackend: "gl", RepoBackend: "gl"},
+			operation:    OperationStart,
+			available:    []BackendCapability{CapabilityIssueTracking},
+			notAvailable: []BackendCapability{CapabilityIssueDetails},
+		},
+		{
+			name:      "finish with undefined backend",
+			profile:   &Profile{Name: "p", IssueBackend: "missing"},
+			operation: OperationFinish,
+			wantErr:   true,
+		},
+		{
+			name:         "finish without issue backend",
+			profile:      &Profile{Name: "p"},
+			operation:    OperationFinish,
+			notAvailable: []BackendCapability{CapabilityIssueTracking},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			support, err := CheckOperationSupport(config, tt.profile, tt.operation)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckOperationSupport() failed: %s", err)
+			}
+			for _, c := range tt.available {
+				if !support.Has(c) {
+					t.Errorf("expected %v to be available", c)
+				}
+			}
+			for _, c := range tt.notAvailable {
+				if support.Has(c) {
+					t.Errorf("expected %v to be unavailable", c)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateProfileBackends tests the ValidateProfileBackends function.
+func TestValidateProfileBackends(t *testing.T) {
+	config := getCapabilitiesTestConfig(t)
+
+	if err := ValidateProfileBackends(config, &Profile{IssueBackend: "jira", RepoBackend: "gh"}); err != nil {
+		t.Fatalf("expected valid profile, got %s", err)
+	}
+	if err := ValidateProfileBackends(config, &Profile{IssueBackend: "jira", RepoBackend: "jira"}); err == nil {
+		t.Fatalf("expected error for jira used as repository backend")
+	}
+	if err := ValidateProfileBackends(config, &Profile{IssueBackend: "nope"}); err == nil {
+		t.Fatalf("expected error for undefined backend")
+	}
+}
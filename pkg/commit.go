@@ -0,0 +1,207 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// commitTemplateData is passed to commitTemplate
+type commitTemplateData struct {
+	ID      IssueID
+	Title   string
+	Branch  string
+	Message string
+}
+
+// issueGitUser returns profile and git user of issue
+func issueGitUser(config IssuectlConfig, issue *IssueConfig) (*Profile, *GitUser, error) {
+	profile, err := getIssueProfile(config, issue)
+	if err != nil {
+		return nil, nil, err
+	}
+	gitUser, found := config.GetGitUser(profile.GitUserName)
+	if !found {
+		return nil, nil, fmt.Errorf("git user %v not found", profile.GitUserName)
+	}
+	return profile, gitUser, nil
+}
+
+// CommitIssue commits staged changes in every repository of issue which has them, with message
+// rendered from commitTemplate of profile or project config. With all, every change is staged first.
+// Commits are made with identity of git user of issue.
+func CommitIssue(ctx context.Context, config IssuectlConfig, issueID IssueID, message string, all bool) error {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return fmt.Errorf("issue %v not found", issueID)
+	}
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("commit message is empty")
+	}
+	profile, gitUser, err := issueGitUser(config, issue)
+	if err != nil {
+		return err
+	}
+	git, err := profileGitClient(profile)
+	if err != nil {
+		return err
+	}
+
+	committed := 0
+	failures := []string{}
+	for _, repoName := range issue.Repositories {
+		dir := filepath.Join(issue.Dir, string(repoName))
+		commit, err := commitRepository(ctx, git, issue, profile, gitUser, dir, message, all)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			Log.Infofp("⚠️", "Failed to commit in %v: %v", repoName, err)
+			failures = append(failures, fmt.Sprintf("%v: %v", repoName, err))
+			continue
+		}
+		if commit == "" {
+			Log.Infofp("⏭️", "%v has no staged changes", repoName)
+			continue
+		}
+		Log.Infofp("📝", "Committed %v in %v", commit, repoName)
+		committed++
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("commit failed in %v of %v repositories:\n  %v",
+			len(failures), len(issue.Repositories), strings.Join(failures, "\n  "))
+	}
+	if committed == 0 {
+		return fmt.Errorf("no repository of issue %v has staged changes", issueID)
+	}
+	return nil
+}
+
+// commitRepository commits staged changes of repository in dir and returns hash and subject of commit,
+// empty when there were no staged changes
+func commitRepository(
+	ctx context.Context,
+	git GitClient,
+	issue *IssueConfig,
+	profile *Profile,
+	gitUser *GitUser,
+	dir, message string,
+	all bool,
+) (string, error) {
+	if !fileExists(dir) {
+		return "", fmt.Errorf("directory %v doesn't exist", dir)
+	}
+	if err := setRepoIdentity(ctx, git, dir, gitUser.Name, gitUser.Email, gitUser.SSHKey); err != nil {
+		return "", err
+	}
+	if all {
+		if err := git.AddAll(ctx, dir); err != nil {
+			return "", err
+		}
+	}
+	staged, err := git.HasStagedChanges(ctx, dir)
+	if err != nil || !staged {
+		return "", err
+	}
+
+	// project config of repository can override commit template of profile
+	effective, err := EffectiveProfile(profile, dir)
+	if err != nil {
+		return "", err
+	}
+	commitTemplate := effective.CommitTemplate
+	if commitTemplate == "" {
+		commitTemplate = defaultCommitTemplate
+	}
+	data := commitTemplateData{issue.ID, issue.Name, issue.BranchName, message}
+	commitMessage, err := renderTemplate("commitTemplate", commitTemplate, data)
+	if err != nil {
+		return "", err
+	}
+
+	if err := git.Commit(ctx, dir, commitMessage); err != nil {
+		return "", err
+	}
+	commit, err := git.LastCommit(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash + " " + commit.Subject, nil
+}
+
+// PushIssue pushes branch checked out in every repository of issue, setting its upstream.
+// With forceWithLease, branches rewritten by sync can be pushed as long as remote didn't change.
+func PushIssue(ctx context.Context, config IssuectlConfig, issueID IssueID, forceWithLease bool) error {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return fmt.Errorf("issue %v not found", issueID)
+	}
+	profile, gitUser, err := issueGitUser(config, issue)
+	if err != nil {
+		return err
+	}
+	git, err := profileGitClient(profile)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for _, repoName := range issue.Repositories {
+		dir := filepath.Join(issue.Dir, string(repoName))
+		if err := pushRepository(ctx, git, gitUser, dir, repoName, forceWithLease); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			Log.Infofp("⚠️", "Failed to push %v: %v", repoName, err)
+			failures = append(failures, fmt.Sprintf("%v: %v", repoName, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("push failed in %v of %v repositories:\n  %v",
+			len(failures), len(issue.Repositories), strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
+// pushRepository pushes branch checked out in dir to origin, unless it's already up to date
+func pushRepository(ctx context.Context, git GitClient, gitUser *GitUser, dir string, name RepoConfigName, forceWithLease bool) error {
+	if !fileExists(dir) {
+		return fmt.Errorf("directory %v doesn't exist", dir)
+	}
+	if err := setRepoIdentity(ctx, git, dir, gitUser.Name, gitUser.Email, gitUser.SSHKey); err != nil {
+		return err
+	}
+	branch, err := git.CurrentBranch(ctx, dir)
+	if err != nil {
+		return err
+	}
+	if branch == "HEAD" {
+		return fmt.Errorf("no branch is checked out")
+	}
+
+	upstream, err := git.Upstream(ctx, dir)
+	if err != nil {
+		return err
+	}
+	commits := "branch"
+	if upstream != "" {
+		comparison, err := git.Compare(ctx, dir, upstream)
+		if err != nil {
+			return err
+		}
+		if comparison.Ahead == 0 {
+			Log.Infofp("✅", "%v has nothing to push to %v", name, upstream)
+			return nil
+		}
+		commits = fmt.Sprintf("%v commits", comparison.Ahead)
+	}
+
+	if err := git.Push(ctx, dir, branch, PushOptions{User: gitUser, ForceWithLease: forceWithLease}); err != nil {
+		return err
+	}
+	Log.Infofp("⬆️", "Pushed %v of %v to origin/%v", commits, name, branch)
+	return nil
+}
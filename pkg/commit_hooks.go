@@ -0,0 +1,146 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// commitHookMarker identifies git hooks installed by issuectl, other hooks are never overwritten
+const commitHookMarker = "# installed by issuectl"
+
+// messagePlaceholder stands for commit message when prefix of commitTemplate is extracted
+const messagePlaceholder = "\x00"
+
+// CommitHooksConfig selects git hooks installed in repositories cloned for issues
+type CommitHooksConfig struct {
+	// Prepare installs prepare-commit-msg hook prefixing messages which don't mention issue key
+	Prepare bool `yaml:"prepare,omitempty"`
+	// Enforce installs commit-msg hook rejecting messages which don't mention issue key
+	Enforce bool `yaml:"enforce,omitempty"`
+}
+
+// installCommitHooks writes git hooks selected in profile to repository in dir
+func installCommitHooks(ctx context.Context, git GitClient, profile *Profile, issue *IssueConfig, dir string) error {
+	if profile.CommitHooks == nil || (!profile.CommitHooks.Prepare && !profile.CommitHooks.Enforce) {
+		return nil
+	}
+	if !gitInstalled() {
+		// hooks are run only by git binary, e.g. go-git client in containers without git doesn't need them
+		Log.V(3).Infof("Not installing commit hooks in %v, git isn't installed", dir)
+		return nil
+	}
+	hooksDir, err := gitHooksDir(ctx, git, dir)
+	if err != nil {
+		return err
+	}
+	if hooksPath, _ := git.GetConfig(ctx, dir, "core.hooksPath"); hooksPath != "" {
+		Log.Infofp("⚠️", "Not installing commit hooks in %v, it uses core.hooksPath %v", filepath.Base(dir), hooksPath)
+		return nil
+	}
+
+	hooks := map[string]string{}
+	if profile.CommitHooks.Prepare {
+		prefix, err := commitMessagePrefix(profile, dir, issue)
+		if err != nil {
+			return err
+		}
+		hooks["prepare-commit-msg"] = prepareCommitMsgHook(issue.ID, prefix)
+	}
+	if profile.CommitHooks.Enforce {
+		hooks["commit-msg"] = commitMsgHook(issue.ID)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+	for _, name := range SortedKeys(hooks) {
+		path := filepath.Join(hooksDir, name)
+		if fileExists(path) && !isIssuectlHook(path) {
+			Log.Infofp("⚠️", "Not installing %v hook in %v, repository already has one", name, filepath.Base(dir))
+			continue
+		}
+		Log.V(3).Infof("Installing %v hook in %v", name, dir)
+		if err := os.WriteFile(path, []byte(hooks[name]), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitHooksDir returns hooks directory inside of .git of repository in dir
+func gitHooksDir(ctx context.Context, git GitClient, dir string) (string, error) {
+	gitDir, err := git.GitDir(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "hooks"), nil
+}
+
+// gitInstalled checks if git binary is available
+func gitInstalled() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+func isIssuectlHook(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), commitHookMarker)
+}
+
+// commitMessagePrefix returns part of commitTemplate before message, so that commits made with git
+// and with issuectl commit look the same. Templates with text after message fall back to default prefix.
+func commitMessagePrefix(profile *Profile, dir string, issue *IssueConfig) (string, error) {
+	effective, err := EffectiveProfile(profile, dir)
+	if err != nil {
+		return "", err
+	}
+	commitTemplate := effective.CommitTemplate
+	if commitTemplate == "" {
+		commitTemplate = defaultCommitTemplate
+	}
+	data := commitTemplateData{issue.ID, issue.Name, issue.BranchName, messagePlaceholder}
+	rendered, err := renderTemplate("commitTemplate", commitTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	if prefix, found := strings.CutSuffix(rendered, messagePlaceholder); found && !strings.Contains(prefix, messagePlaceholder) {
+		return prefix, nil
+	}
+	return string(issue.ID) + ": ", nil
+}
+
+// prepareCommitMsgHook prefixes first line of message without issue key, merges and amended commits are left as they are.
+// Key is matched as whole word, so that PROJ-7 isn't found in PROJ-70.
+func prepareCommitMsgHook(issueID IssueID, prefix string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%v
+key=%v
+prefix=%v
+case "$2" in
+    merge|squash|commit) exit 0 ;;
+esac
+grep -v '^#' "$1" | grep -qwF -- "$key" && exit 0
+{ printf '%%s' "$prefix"; cat "$1"; } > "$1.issuectl" && mv "$1.issuectl" "$1"
+`, commitHookMarker, shellQuote(string(issueID)), shellQuote(prefix))
+}
+
+// commitMsgHook rejects messages without issue key, except of merge commits
+func commitMsgHook(issueID IssueID) string {
+	return fmt.Sprintf(`#!/bin/sh
+%v
+key=%v
+[ -f "$(git rev-parse --git-path MERGE_HEAD)" ] && exit 0
+grep -v '^#' "$1" | grep -qwF -- "$key" && exit 0
+echo "issuectl: commit message has to mention issue $key" >&2
+exit 1
+`, commitHookMarker, shellQuote(string(issueID)))
+}
+
+// shellQuote quotes s for POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,64 @@
+package issuectl
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func startCommitHooksTest(t *testing.T, hooks *CommitHooksConfig) string {
+	t.Helper()
+	env := newTestEnv(t, nil, nil, "service")
+	env.updateProfile(t, func(profile *Profile) {
+		profile.CommitHooks = hooks
+		profile.CommitTemplate = "[{{.ID}}] {{.Message}}"
+	})
+	if err := StartWorkingOnIssue(context.Background(), "", env.config, "PROJ-7"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ := env.config.GetIssue("PROJ-7")
+	return filepath.Join(issue.Dir, "service")
+}
+
+func lastCommitMessage(t *testing.T, dir string) string {
+	t.Helper()
+	message, err := gitOutput(context.Background(), dir, "log", "-1", "--format=%s")
+	if err != nil {
+		t.Fatalf("git log failed: %s", err)
+	}
+	return message
+}
+
+// TestPrepareCommitMsgHook tests that issue key is added to commit messages missing it.
+func TestPrepareCommitMsgHook(t *testing.T) {
+	dir := startCommitHooksTest(t, &CommitHooksConfig{Prepare: true, Enforce: true})
+
+	runGitFixture(t, dir, "commit", "--allow-empty", "-m", "Fix login")
+	if message := lastCommitMessage(t, dir); message != "[PROJ-7] Fix login" {
+		t.Errorf("expected prefixed message, got %q", message)
+	}
+	runGitFixture(t, dir, "commit", "--allow-empty", "-m", "Refactor for PROJ-7")
+	if message := lastCommitMessage(t, dir); message != "Refactor for PROJ-7" {
+		t.Errorf("expected message with key to be kept, got %q", message)
+	}
+	runGitFixture(t, dir, "commit", "--allow-empty", "-m", "Follow up of PROJ-70")
+	if message := lastCommitMessage(t, dir); message != "[PROJ-7] Follow up of PROJ-70" {
+		t.Errorf("expected message mentioning other issue to be prefixed, got %q", message)
+	}
+}
+
+// TestCommitMsgHook tests that commits without issue key are rejected.
+func TestCommitMsgHook(t *testing.T) {
+	dir := startCommitHooksTest(t, &CommitHooksConfig{Enforce: true})
+
+	for _, message := range []string{"Fix login", "Fix login for PROJ-70"} {
+		cmd := exec.Command("git", "-c", "user.name=issuectl", "-c", "user.email=issuectl@example.com",
+			"commit", "--allow-empty", "-m", message)
+		cmd.Dir = dir
+		if err := cmd.Run(); err == nil {
+			t.Errorf("expected commit %q without issue key to be rejected", message)
+		}
+	}
+	runGitFixture(t, dir, "commit", "--allow-empty", "-m", "PROJ-7 Fix login")
+}
@@ -0,0 +1,56 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCommitAndPushIssue tests committing staged changes with issue prefix and pushing only repositories with new commits.
+func TestCommitAndPushIssue(t *testing.T) {
+	for _, name := range gitClients {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t, nil, nil, "api", "web")
+			env.setGitClient(t, name)
+			env.updateProfile(t, func(profile *Profile) { profile.CommitTemplate = "[{{.ID}}] {{.Message}}" })
+			if err := StartWorkingOnIssue(ctx, "", env.config, "13"); err != nil {
+				t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+			}
+			issue, _ := env.config.GetIssue("13")
+			apiDir := filepath.Join(issue.Dir, "api")
+
+			if err := CommitIssue(ctx, env.config, "13", "Fix login", false); err == nil {
+				t.Errorf("expected error when no repository has staged changes")
+			}
+
+			if err := os.WriteFile(filepath.Join(apiDir, "login.go"), []byte("package login\n"), 0644); err != nil {
+				t.Fatalf("failed to write file: %s", err)
+			}
+			if err := CommitIssue(ctx, env.config, "13", "Fix login", true); err != nil {
+				t.Fatalf("CommitIssue() failed: %s", err)
+			}
+			commit, err := gitOutput(ctx, apiDir, "log", "-1", "--format=%an <%ae> %s")
+			if err != nil {
+				t.Fatalf("git log failed: %s", err)
+			}
+			if expected := "tester <tester@example.com> [13] Fix login"; commit != expected {
+				t.Errorf("expected commit %q, got %q", expected, commit)
+			}
+
+			if err := PushIssue(ctx, env.config, "13", false); err != nil {
+				t.Fatalf("PushIssue() failed: %s", err)
+			}
+			for repo, expected := range map[RepoConfigName]string{"api": "[13] Fix login", "web": "Initial commit"} {
+				pushed, err := gitOutput(ctx, env.repos[repo].dir, "log", "-1", "--format=%s", issue.BranchName)
+				if err != nil {
+					t.Fatalf("git log in %v failed: %s", repo, err)
+				}
+				if pushed != expected {
+					t.Errorf("expected last commit of %v on remote to be %q, got %q", repo, expected, pushed)
+				}
+			}
+		})
+	}
+}
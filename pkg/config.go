@@ -2,23 +2,12 @@ package issuectl
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
-	"log"
 	"os"
 	"path/filepath"
-
-	"gopkg.in/yaml.v2"
 )
 
-func getDefaultConfigFilePath() string {
-	dirname, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return filepath.Join(dirname, ".issuerc")
-}
-
 func getDefaultSSHKeyPath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -27,11 +16,11 @@ func getDefaultSSHKeyPath() string {
 	return filepath.Join(home, ".ssh/id_ed25519")
 }
 
-var DefaultConfigFilePath = getDefaultConfigFilePath()
 var DefaultSSHKeyPath = getDefaultSSHKeyPath()
 
 // IssuectlConfig manages configuration
 type issuectlConfig struct {
+	Version        int                                  `yaml:"version"`
 	CurrentProfile ProfileName                          `yaml:"currentProfile"`
 	Repositories   map[RepoConfigName]*RepoConfig       `yaml:"repositories,omitempty"`
 	Issues         map[IssueID]*IssueConfig             `yaml:"issues,omitempty"`
@@ -41,11 +30,19 @@ type issuectlConfig struct {
 	Config         TextConfig                           `yaml:"config,omitempty"`
 
 	_persistenceMode string `yaml:"-"`
+	// _path is location of config file
+	_path string `yaml:"-"`
+	// _snapshot is content of config file at the time config was loaded
+	_snapshot []byte `yaml:"-"`
 }
 
 type IssuectlConfig interface {
 	GetInMemory() IssuectlConfig
 	GetPersistent() IssuectlConfig
+	// GetPath returns location of config file
+	GetPath() string
+	// WithPath sets location config file is saved to
+	WithPath(path string) IssuectlConfig
 
 	// Profile
 	AddProfile(*Profile) error
@@ -53,6 +50,8 @@ type IssuectlConfig interface {
 	GetCurrentProfile() ProfileName
 	GetProfile(ProfileName) *Profile
 	GetProfiles() map[ProfileName]*Profile
+	// ResolveProfile returns profile with inherited settings and profiles each setting comes from
+	ResolveProfile(ProfileName) (*Profile, ProfileOrigins, error)
 	UpdateProfile(*Profile) error
 	UseProfile(profile ProfileName) error
 
@@ -64,6 +63,7 @@ type IssuectlConfig interface {
 
 	// Repositories
 	AddRepository(repoConfig *RepoConfig) error
+	DeleteRepository(name RepoConfigName) error
 	GetRepository(name RepoConfigName) *RepoConfig
 	GetRepositories() map[RepoConfigName]*RepoConfig
 
@@ -79,57 +79,62 @@ type IssuectlConfig interface {
 	GetGitUser(userName GitUserName) (*GitUser, bool)
 	GetGitUsers() map[GitUserName]*GitUser
 
-	Save() error // TODO: this shouldn't be exposed
-}
+	// Set sets value of config field at dot separated path, e.g. profiles.work.workDir
+	Set(path string, value string) error
+	// Unset removes config field at dot separated path
+	Unset(path string) error
 
-var persistentFlagHandle = func(c IssuectlConfig) error {
-	y, err := yaml.Marshal(c)
-	if err != nil {
-		return err
-	}
+	// Validate checks references between config entities
+	Validate() error
 
-	file, err := os.OpenFile(
-		DefaultConfigFilePath,
-		os.O_TRUNC|os.O_CREATE|os.O_WRONLY,
-		0644,
-	)
-	if err != nil {
-		log.Fatalf("error: %v", err)
-	}
-	defer file.Close()
+	Save() error // TODO: this shouldn't be exposed
+}
 
-	_, err = file.WriteString(string(y))
-	if err != nil {
-		log.Fatalf("error: %v", err)
+var persistentFlagHandle = func(c *issuectlConfig) error {
+	if c._path == "" {
+		return fmt.Errorf("config file path not set")
 	}
-	return nil
+	return writeConfigFile(c._path, c)
 }
 
-var inMemoryFlagHandle = func(_ IssuectlConfig) error { return nil }
+var inMemoryFlagHandle = func(_ *issuectlConfig) error { return nil }
 
-func LoadConfig() IssuectlConfig {
-	config := GetEmptyConfig()
+// LoadConfig reads config file from path, migrating it to CurrentConfigVersion if needed.
+// Missing config file results in empty config, nothing is written until it is saved.
+func LoadConfig(path string) (IssuectlConfig, error) {
+	config := GetEmptyConfig().(*issuectlConfig)
+	config._path = path
 
-	data, err := os.ReadFile(DefaultConfigFilePath)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			if err := config.Save(); err != nil {
-				return config
-			}
-			return config
+			return config, nil
 		}
-		return nil
+		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	if err = yaml.Unmarshal(data, config); err != nil {
-		return nil
+	migrated, changed, err := migrateConfig(path, data)
+	if err != nil {
+		return nil, err
 	}
 
-	return config
+	if err := unmarshalConfigStrict(path, migrated, config); err != nil {
+		return nil, err
+	}
+	config._snapshot = data
+
+	if changed {
+		if err := writeConfigFile(path, config); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+	}
+
+	return config, nil
 }
 
 func GetEmptyConfig() IssuectlConfig {
 	return &issuectlConfig{
+		Version:      CurrentConfigVersion,
 		Repositories: map[RepoConfigName]*RepoConfig{},
 		Issues:       map[IssueID]*IssueConfig{},
 		Profiles:     map[ProfileName]*Profile{},
@@ -139,13 +144,21 @@ func GetEmptyConfig() IssuectlConfig {
 }
 
 func GetConfig(cn ProfileName, r map[RepoConfigName]*RepoConfig, b map[BackendConfigName]*BackendConfig, gu map[GitUserName]*GitUser, p map[ProfileName]*Profile) IssuectlConfig {
-	return &issuectlConfig{
-		CurrentProfile: cn,
-		Repositories:   r,
-		Profiles:       p,
-		Backends:       b,
-		GitUsers:       gu,
+	config := GetEmptyConfig().(*issuectlConfig)
+	config.CurrentProfile = cn
+	if r != nil {
+		config.Repositories = r
+	}
+	if b != nil {
+		config.Backends = b
 	}
+	if gu != nil {
+		config.GitUsers = gu
+	}
+	if p != nil {
+		config.Profiles = p
+	}
+	return config
 }
 
 func (ic *issuectlConfig) Save() error {
@@ -169,6 +182,15 @@ func (ic *issuectlConfig) GetInMemory() IssuectlConfig {
 	return ic
 }
 
+func (ic *issuectlConfig) GetPath() string {
+	return ic._path
+}
+
+func (ic *issuectlConfig) WithPath(path string) IssuectlConfig {
+	ic._path = path
+	return ic
+}
+
 // Issues
 
 func (ic *issuectlConfig) AddIssue(issueConfig *IssueConfig) error {
@@ -201,22 +223,59 @@ func (ic *issuectlConfig) AddRepository(repoConfig *RepoConfig) error {
 	return ic.Save()
 }
 
+func (ic *issuectlConfig) DeleteRepository(name RepoConfigName) error {
+	if _, found := ic.Repositories[name]; !found {
+		return fmt.Errorf("repository %v not defined", name)
+	}
+	if err := checkNotInUse("repository", string(name), ic.repositoryUsers(name)); err != nil {
+		return err
+	}
+	delete(ic.Repositories, name)
+	return ic.Save()
+}
+
 func (ic *issuectlConfig) GetRepositories() map[RepoConfigName]*RepoConfig {
 	return ic.Repositories
 }
 
 // Profiles
 
+// GetProfile returns profile with settings inherited from profiles it extends.
+// Returned profile is always a copy, changes have to be stored with UpdateProfile.
 func (ic *issuectlConfig) GetProfile(profileName ProfileName) *Profile {
-	return ic.Profiles[profileName]
+	profile := ic.Profiles[profileName]
+	if profile == nil {
+		return nil
+	}
+	resolved, _, err := ic.ResolveProfile(profileName)
+	if err != nil {
+		// broken inheritance is reported by Validate
+		Log.V(3).Infof("Failed to resolve profile %v: %v", profileName, err)
+		return copyProfile(profile)
+	}
+	return resolved
+}
+
+func (ic *issuectlConfig) ResolveProfile(profileName ProfileName) (*Profile, ProfileOrigins, error) {
+	return resolveProfile(ic.Profiles, profileName)
 }
 
 func (ic *issuectlConfig) AddProfile(profile *Profile) error {
-	ic.Profiles[profile.Name] = profile
+	stored, err := unresolveProfile(ic.Profiles, profile)
+	if err != nil {
+		return err
+	}
+	ic.Profiles[profile.Name] = stored
 	return ic.Save()
 }
 
 func (ic *issuectlConfig) DeleteProfile(profileName ProfileName) error {
+	if _, found := ic.Profiles[profileName]; !found {
+		return fmt.Errorf("profile %v not defined", profileName)
+	}
+	if err := checkNotInUse("profile", string(profileName), ic.profileUsers(profileName)); err != nil {
+		return err
+	}
 	delete(ic.Profiles, profileName)
 	return ic.Save()
 }
@@ -230,13 +289,18 @@ func (ic *issuectlConfig) UseProfile(profile ProfileName) error {
 	return ic.Save()
 }
 
+// GetProfiles returns all profiles with inherited settings resolved
 func (ic *issuectlConfig) GetProfiles() map[ProfileName]*Profile {
-	return ic.Profiles
+	profiles := make(map[ProfileName]*Profile, len(ic.Profiles))
+	for name := range ic.Profiles {
+		profiles[name] = ic.GetProfile(name)
+	}
+	return profiles
 }
 
+// UpdateProfile stores profile, settings equal to inherited ones aren't stored
 func (ic *issuectlConfig) UpdateProfile(profile *Profile) error {
-	ic.Profiles[profile.Name] = profile
-	return ic.Save()
+	return ic.AddProfile(profile)
 }
 
 // Backends
@@ -250,6 +314,12 @@ func (ic *issuectlConfig) AddBackend(backend *BackendConfig) error {
 }
 
 func (ic *issuectlConfig) DeleteBackend(backendName BackendConfigName) error {
+	if _, found := ic.Backends[backendName]; !found {
+		return fmt.Errorf("backend %v not defined", backendName)
+	}
+	if err := checkNotInUse("backend", string(backendName), ic.backendUsers(backendName)); err != nil {
+		return err
+	}
 	delete(ic.Backends, backendName)
 	return ic.Save()
 }
@@ -270,6 +340,12 @@ func (ic *issuectlConfig) AddGitUser(user *GitUser) error {
 }
 
 func (ic *issuectlConfig) DeleteGitUser(userName GitUserName) error {
+	if _, found := ic.GitUsers[userName]; !found {
+		return fmt.Errorf("git user %v not defined", userName)
+	}
+	if err := checkNotInUse("git user", string(userName), ic.gitUserUsers(userName)); err != nil {
+		return err
+	}
 	delete(ic.GitUsers, userName)
 	return ic.Save()
 }
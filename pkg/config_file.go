@@ -0,0 +1,201 @@
+package issuectl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ErrConcurrentModification is returned when config file was changed by another issuectl run
+// in a way that conflicts with changes being saved.
+var ErrConcurrentModification = errors.New("config file was modified concurrently")
+
+// writeConfigFile saves config to path. While holding lock on the config file it checks whether
+// file changed since config was loaded and merges non conflicting changes made in the meantime.
+// File is replaced atomically, so it's never left partially written.
+func writeConfigFile(path string, ic *issuectlConfig) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock config file: %w", err)
+	}
+	defer lock.Unlock()
+
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if !bytes.Equal(current, ic._snapshot) {
+		Log.V(3).Infof("Config file %v changed since it was loaded, merging changes", path)
+		if err := ic.mergeConcurrentChanges(current); err != nil {
+			return err
+		}
+	}
+
+	data, err := yaml.Marshal(ic)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	ic._snapshot = data
+	return nil
+}
+
+// writeFileAtomic writes data to temporary file next to path and renames it over path.
+// Permissions of existing file are preserved.
+func writeFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// mergeConcurrentChanges applies changes made to the file since config was loaded (base snapshot)
+// on top of in memory config. Entries changed on both sides to different values are conflicts.
+func (ic *issuectlConfig) mergeConcurrentChanges(current []byte) error {
+	base := &issuectlConfig{}
+	if err := yaml.Unmarshal(ic._snapshot, base); err != nil {
+		return fmt.Errorf("failed to parse loaded config: %w", err)
+	}
+	theirs := &issuectlConfig{}
+	if err := yaml.Unmarshal(current, theirs); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	conflicts := []string{}
+	addConflicts := func(section string, keys []string) {
+		for _, key := range keys {
+			conflicts = append(conflicts, fmt.Sprintf("%v.%v", section, key))
+		}
+	}
+
+	var keys []string
+	ic.Repositories, keys = mergeEntries(base.Repositories, theirs.Repositories, ic.Repositories)
+	addConflicts("repositories", keys)
+	ic.Issues, keys = mergeEntries(base.Issues, theirs.Issues, ic.Issues)
+	addConflicts("issues", keys)
+	ic.Profiles, keys = mergeEntries(base.Profiles, theirs.Profiles, ic.Profiles)
+	addConflicts("profiles", keys)
+	ic.Backends, keys = mergeEntries(base.Backends, theirs.Backends, ic.Backends)
+	addConflicts("backends", keys)
+	ic.GitUsers, keys = mergeEntries(base.GitUsers, theirs.GitUsers, ic.GitUsers)
+	addConflicts("gitUsers", keys)
+
+	var conflict bool
+	ic.CurrentProfile, conflict = mergeValue(base.CurrentProfile, theirs.CurrentProfile, ic.CurrentProfile)
+	if conflict {
+		conflicts = append(conflicts, "currentProfile")
+	}
+	ic.Config, conflict = mergeValue(base.Config, theirs.Config, ic.Config)
+	if conflict {
+		conflicts = append(conflicts, "config")
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf(
+			"%w - conflicting changes to %v, run the command again",
+			ErrConcurrentModification, strings.Join(conflicts, ", "),
+		)
+	}
+	return nil
+}
+
+// mergeEntries does three way merge of config map section
+func mergeEntries[K ~string, V any](base, theirs, ours map[K]V) (map[K]V, []string) {
+	merged := map[K]V{}
+	conflicts := []string{}
+
+	keys := map[K]bool{}
+	for _, m := range []map[K]V{base, theirs, ours} {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+
+	for k := range keys {
+		baseValue, inBase := base[k]
+		theirValue, inTheirs := theirs[k]
+		ourValue, inOurs := ours[k]
+
+		oursChanged := inOurs != inBase || (inOurs && !sameValue(ourValue, baseValue))
+		theirsChanged := inTheirs != inBase || (inTheirs && !sameValue(theirValue, baseValue))
+
+		switch {
+		case !oursChanged:
+			if inTheirs {
+				merged[k] = theirValue
+			}
+		case !theirsChanged || (inOurs == inTheirs && (!inOurs || sameValue(ourValue, theirValue))):
+			if inOurs {
+				merged[k] = ourValue
+			}
+		default:
+			conflicts = append(conflicts, string(k))
+			if inOurs {
+				merged[k] = ourValue
+			}
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeValue does three way merge of single config value, reports conflict when both sides changed it
+func mergeValue[V any](base, theirs, ours V) (V, bool) {
+	switch {
+	case sameValue(ours, base):
+		return theirs, false
+	case sameValue(theirs, base), sameValue(ours, theirs):
+		return ours, false
+	default:
+		return ours, true
+	}
+}
+
+// sameValue compares config values by their serialized form
+func sameValue(a, b interface{}) bool {
+	ay, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	by, err := yaml.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ay, by)
+}
@@ -0,0 +1,93 @@
+package issuectl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tempConfigPath returns path of config file in temporary directory
+func tempConfigPath(t *testing.T) string {
+	t.Helper()
+	return filepath.Join(t.TempDir(), "config.yaml")
+}
+
+func mustLoadConfig(t *testing.T, path string) IssuectlConfig {
+	t.Helper()
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %s", err)
+	}
+	return config
+}
+
+// TestConcurrentSavesAreMerged tests that saves of configs loaded at the same time don't lose changes.
+func TestConcurrentSavesAreMerged(t *testing.T) {
+	path := tempConfigPath(t)
+	if err := GetConfig("default", nil, nil, nil, nil).WithPath(path).GetPersistent().Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	first := mustLoadConfig(t, path).GetPersistent()
+	second := mustLoadConfig(t, path).GetPersistent()
+
+	if err := first.AddIssue(&IssueConfig{ID: "1"}); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+	if err := second.AddIssue(&IssueConfig{ID: "2"}); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+
+	issues := mustLoadConfig(t, path).GetIssues()
+	for _, issueID := range []IssueID{"1", "2"} {
+		if _, found := issues[issueID]; !found {
+			t.Errorf("expected issue %v in saved config", issueID)
+		}
+	}
+}
+
+// TestConflictingSaveFails tests that conflicting concurrent changes are detected.
+func TestConflictingSaveFails(t *testing.T) {
+	path := tempConfigPath(t)
+	config := GetConfig("default", nil, nil, nil, map[ProfileName]*Profile{
+		"default": {Name: "default", WorkDir: "/work"},
+	}).WithPath(path).GetPersistent()
+	if err := config.Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	first := mustLoadConfig(t, path).GetPersistent()
+	second := mustLoadConfig(t, path).GetPersistent()
+
+	if err := first.UpdateProfile(&Profile{Name: "default", WorkDir: "/first"}); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+	err := second.UpdateProfile(&Profile{Name: "default", WorkDir: "/second"})
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+
+	if workDir := mustLoadConfig(t, path).GetProfile("default").WorkDir; workDir != "/first" {
+		t.Errorf("expected first change to be kept, got workDir %v", workDir)
+	}
+}
+
+// TestSaveLeavesNoTemporaryFiles tests that atomic write cleans up after itself.
+func TestSaveLeavesNoTemporaryFiles(t *testing.T) {
+	path := tempConfigPath(t)
+	if err := GetConfig("default", nil, nil, nil, nil).WithPath(path).GetPersistent().Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read config dir: %s", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %v left behind", entry.Name())
+		}
+	}
+}
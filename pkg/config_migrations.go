@@ -0,0 +1,231 @@
+package issuectl
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CurrentConfigVersion is a version of config schema used by this issuectl build
+const CurrentConfigVersion = 1
+
+// configMigration upgrades raw config document to given version
+type configMigration struct {
+	version     int
+	description string
+	migrate     func(doc map[interface{}]interface{}) error
+}
+
+// configMigrations have to be sorted by version, each upgrading from version-1
+var configMigrations = []configMigration{
+	{
+		version:     1,
+		description: "use camelCase keys for git users",
+		migrate:     migrateGitUserKeys,
+	},
+}
+
+// migrateGitUserKeys renames sshkey to sshKey in every git user
+func migrateGitUserKeys(doc map[interface{}]interface{}) error {
+	users, ok := doc["gitUsers"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	for _, u := range users {
+		user, ok := u.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		if key, found := user["sshkey"]; found {
+			user["sshKey"] = key
+			delete(user, "sshkey")
+		}
+	}
+	return nil
+}
+
+// getConfigVersion reads schema version from raw config document, files without it are version 0
+func getConfigVersion(doc map[interface{}]interface{}) (int, error) {
+	raw, found := doc["version"]
+	if !found {
+		return 0, nil
+	}
+	version, ok := raw.(int)
+	if !ok {
+		return 0, fmt.Errorf("version has to be a number, got %v", raw)
+	}
+	return version, nil
+}
+
+// migrateConfig upgrades config file data to CurrentConfigVersion. Before changing anything
+// copy of the original file is stored next to it. Returns migrated data and whether anything changed.
+func migrateConfig(path string, data []byte) ([]byte, bool, error) {
+	doc := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("config file %v is not valid YAML: %w", path, err)
+	}
+
+	version, err := getConfigVersion(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("config file %v: %w", path, err)
+	}
+	if version > CurrentConfigVersion {
+		return nil, false, fmt.Errorf(
+			"config file %v has version %v, this issuectl supports up to %v - please upgrade issuectl",
+			path, version, CurrentConfigVersion,
+		)
+	}
+	if version == CurrentConfigVersion {
+		return data, false, nil
+	}
+
+	backupPath := fmt.Sprintf("%v.v%v.bak", path, version)
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return nil, false, fmt.Errorf("failed to back up config before migration: %w", err)
+	}
+	Log.Infofp("📦", "Migrating config from version %v to %v (backup saved to %v)", version, CurrentConfigVersion, backupPath)
+
+	for _, migration := range configMigrations {
+		if migration.version <= version {
+			continue
+		}
+		Log.V(2).Infof("Config migration to version %v: %v", migration.version, migration.description)
+		if err := migration.migrate(doc); err != nil {
+			return nil, false, fmt.Errorf("config migration to version %v failed: %w", migration.version, err)
+		}
+		doc["version"] = migration.version
+	}
+
+	migrated, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	return migrated, true, nil
+}
+
+var unknownFieldError = regexp.MustCompile(`^line (\d+): field (\S+) not found in type (\S+)$`)
+
+// configTypes maps type names used by yaml errors to config types
+var configTypes = map[string]reflect.Type{
+	"issuectl.issuectlConfig": reflect.TypeOf(issuectlConfig{}),
+	"issuectl.RepoConfig":     reflect.TypeOf(RepoConfig{}),
+	"issuectl.IssueConfig":    reflect.TypeOf(IssueConfig{}),
+	"issuectl.Profile":        reflect.TypeOf(Profile{}),
+	"issuectl.BackendConfig":  reflect.TypeOf(BackendConfig{}),
+	"issuectl.GitHubConfig":   reflect.TypeOf(GitHubConfig{}),
+	"issuectl.GitLabConfig":   reflect.TypeOf(GitLabConfig{}),
+	"issuectl.JiraConfig":     reflect.TypeOf(JiraConfig{}),
+	"issuectl.GitUser":        reflect.TypeOf(GitUser{}),
+	"issuectl.TextConfig":     reflect.TypeOf(TextConfig{}),
+	"issuectl.SecretSource":   reflect.TypeOf(SecretSource{}),
+	"issuectl.ProjectConfig":  reflect.TypeOf(ProjectConfig{}),
+	"issuectl.Bundle":         reflect.TypeOf(Bundle{}),
+}
+
+// unmarshalConfigStrict parses config data rejecting unknown fields with readable errors
+func unmarshalConfigStrict(path string, data []byte, config interface{}) error {
+	err := yaml.UnmarshalStrict(data, config)
+	if err == nil {
+		return nil
+	}
+	typeErr, ok := err.(*yaml.TypeError)
+	if !ok {
+		return fmt.Errorf("config file %v is not valid YAML: %w", path, err)
+	}
+
+	problems := []string{}
+	for _, e := range typeErr.Errors {
+		problems = append(problems, "  "+describeConfigError(e))
+	}
+	return fmt.Errorf("config file %v is invalid:\n%v", path, strings.Join(problems, "\n"))
+}
+
+// describeConfigError rewrites yaml unknown field errors into friendlier message with suggestion
+func describeConfigError(message string) string {
+	match := unknownFieldError.FindStringSubmatch(message)
+	if match == nil {
+		return message
+	}
+	line, field, typeName := match[1], match[2], match[3]
+
+	description := fmt.Sprintf("line %v: unknown field %q", line, field)
+	configType, found := configTypes[typeName]
+	if !found {
+		return description
+	}
+	description = fmt.Sprintf("%v in %v", description, strings.TrimPrefix(typeName, "issuectl."))
+	if suggestion := suggestField(field, yamlFieldNames(configType)); suggestion != "" {
+		description = fmt.Sprintf("%v, did you mean %q?", description, suggestion)
+	}
+	return description
+}
+
+// yamlFieldName returns name of struct field in yaml document
+func yamlFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// yamlFieldNames lists keys that yaml accepts for struct type
+func yamlFieldNames(t reflect.Type) []string {
+	names := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := yamlFieldName(field)
+		if name == "-" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// suggestField finds known field closest to unknown one
+func suggestField(field string, known []string) string {
+	best, bestDistance := "", 3
+	for _, name := range known {
+		if strings.EqualFold(name, field) {
+			return name
+		}
+		if d := levenshtein(strings.ToLower(field), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,94 @@
+package issuectl
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const unversionedConfig = `currentProfile: default
+gitUsers:
+  John Doe:
+    name: John Doe
+    email: john@doe.com
+    sshkey: /home/john/.ssh/id_rsa
+`
+
+// TestLoadConfigMigratesOldVersion tests migration of config file without version.
+func TestLoadConfigMigratesOldVersion(t *testing.T) {
+	path := tempConfigPath(t)
+	if err := os.WriteFile(path, []byte(unversionedConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %s", err)
+	}
+
+	config := mustLoadConfig(t, path)
+	gitUser, found := config.GetGitUser("John Doe")
+	if !found {
+		t.Fatalf("git user not found after migration")
+	}
+	if gitUser.SSHKey != "/home/john/.ssh/id_rsa" {
+		t.Errorf("expected ssh key to be migrated, got %q", gitUser.SSHKey)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("expected backup of original config: %s", err)
+	}
+	if string(backup) != unversionedConfig {
+		t.Errorf("backup doesn't match original config")
+	}
+
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read migrated config: %s", err)
+	}
+	if !strings.Contains(string(migrated), "version: 1") || !strings.Contains(string(migrated), "sshKey:") {
+		t.Errorf("expected migrated config to be saved, got:\n%s", migrated)
+	}
+}
+
+// TestLoadConfigErrors tests errors returned for invalid config files.
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:     "unknown field with suggestion",
+			config:   "version: 1\nprofiles:\n  default:\n    name: default\n    workdir: /work\n",
+			expected: `line 5: unknown field "workdir" in Profile, did you mean "workDir"?`,
+		},
+		{
+			name:     "unknown top level field",
+			config:   "version: 1\nfoo: bar\n",
+			expected: `line 2: unknown field "foo"`,
+		},
+		{
+			name:     "newer version",
+			config:   "version: 99\n",
+			expected: "please upgrade issuectl",
+		},
+		{
+			name:     "invalid yaml",
+			config:   "version: [1\n",
+			expected: "is not valid YAML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tempConfigPath(t)
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatalf("failed to write config: %s", err)
+			}
+			config, err := LoadConfig(path)
+			if err == nil {
+				t.Fatalf("expected error, got config %v", config)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %q", tt.expected, err)
+			}
+		})
+	}
+}
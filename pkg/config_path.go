@@ -0,0 +1,65 @@
+package issuectl
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// EnvConfigPath is environment variable pointing to config file, overridden by --config flag
+	EnvConfigPath = "ISSUECTL_CONFIG"
+
+	configDirName  = "issuectl"
+	configFileName = "config.yaml"
+	// legacyConfigFileName is config file in home directory used before XDG support
+	legacyConfigFileName = ".issuerc"
+)
+
+// ResolveConfigPath returns path of config file. First non empty of explicit path (--config flag),
+// $ISSUECTL_CONFIG and $XDG_CONFIG_HOME/issuectl/config.yaml is used. When XDG config doesn't exist
+// yet but ~/.issuerc does, the old location is kept.
+func ResolveConfigPath(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path, nil
+	}
+
+	configDir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(configDir, configFileName)
+	if fileExists(path) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if legacy := filepath.Join(home, legacyConfigFileName); fileExists(legacy) {
+			return legacy, nil
+		}
+	}
+	return path, nil
+}
+
+// userConfigDir returns $XDG_CONFIG_HOME/issuectl, defaulting to ~/.config/issuectl on all platforms
+func userConfigDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, configDirName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("can't determine config location, use --config or %v: %w", EnvConfigPath, err)
+	}
+	return filepath.Join(home, ".config", configDirName), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
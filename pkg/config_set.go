@@ -0,0 +1,171 @@
+package issuectl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Set parses value as YAML and stores it under dot separated path, e.g. profiles.work.workDir.
+// Change introducing new problems reported by Validate, like reference to missing entity, is rejected.
+// Config entities are replaced, so entities obtained before the change have to be fetched again.
+func (ic *issuectlConfig) Set(path string, value string) error {
+	return ic.modify(path, func(target reflect.Value) (reflect.Value, error) {
+		parsed := reflect.New(target.Type())
+		if err := yaml.UnmarshalStrict([]byte(value), parsed.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid value for %v: %w", path, err)
+		}
+		return parsed.Elem(), nil
+	})
+}
+
+// Unset removes value stored under dot separated path. Removing whole entity, e.g. profiles.work,
+// is rejected when it's still referenced.
+func (ic *issuectlConfig) Unset(path string) error {
+	return ic.modify(path, func(target reflect.Value) (reflect.Value, error) {
+		return reflect.Zero(target.Type()), nil
+	})
+}
+
+// modify applies change to copy of config and replaces config with it if it doesn't break references
+func (ic *issuectlConfig) modify(path string, change func(target reflect.Value) (reflect.Value, error)) error {
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("invalid config path %q", path)
+		}
+	}
+
+	updated, err := ic.clone()
+	if err != nil {
+		return err
+	}
+
+	if err := setPath(reflect.ValueOf(updated).Elem(), segments, nil, change); err != nil {
+		return err
+	}
+
+	if problems := newProblems(ic.Validate(), updated.Validate()); len(problems) > 0 {
+		return fmt.Errorf("can't change %v: %w", path, newValidationError(problems))
+	}
+
+	ic.replaceWith(updated)
+	return ic.Save()
+}
+
+// clone returns deep copy of config data, without persistence settings
+func (ic *issuectlConfig) clone() (*issuectlConfig, error) {
+	data, err := yaml.Marshal(ic)
+	if err != nil {
+		return nil, err
+	}
+	cloned := GetEmptyConfig().(*issuectlConfig)
+	if err := yaml.Unmarshal(data, cloned); err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// replaceWith replaces config data with data of updated, keeping persistence settings
+func (ic *issuectlConfig) replaceWith(updated *issuectlConfig) {
+	updated._persistenceMode = ic._persistenceMode
+	updated._path = ic._path
+	updated._snapshot = ic._snapshot
+	*ic = *updated
+}
+
+// setPath walks config structure following yaml names of fields and map keys and applies change to target value
+func setPath(value reflect.Value, segments, walked []string, change func(target reflect.Value) (reflect.Value, error)) error {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			if !value.CanSet() {
+				return fmt.Errorf("%v is empty", strings.Join(walked, "."))
+			}
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	segment := segments[0]
+	walked = append(walked, segment)
+	last := len(segments) == 1
+
+	switch value.Kind() {
+	case reflect.Struct:
+		known := []string{}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name := yamlFieldName(field)
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			known = append(known, name)
+			if name != segment {
+				continue
+			}
+			if last {
+				changed, err := change(value.Field(i))
+				if err != nil {
+					return err
+				}
+				value.Field(i).Set(changed)
+				return nil
+			}
+			return setPath(value.Field(i), segments[1:], walked, change)
+		}
+		message := fmt.Sprintf("unknown config field %v", strings.Join(walked, "."))
+		if suggestion := suggestField(segment, known); suggestion != "" {
+			message = fmt.Sprintf("%v, did you mean %q?", message, suggestion)
+		}
+		return fmt.Errorf("%v", message)
+
+	case reflect.Map:
+		key := reflect.ValueOf(segment).Convert(value.Type().Key())
+		entry := value.MapIndex(key)
+		if !entry.IsValid() {
+			if last {
+				return fmt.Errorf("%v not defined, use add command to create it", strings.Join(walked, "."))
+			}
+			return fmt.Errorf("%v not defined", strings.Join(walked, "."))
+		}
+		if last {
+			changed, err := change(entry)
+			if err != nil {
+				return err
+			}
+			if changed.IsZero() {
+				value.SetMapIndex(key, reflect.Value{})
+			} else {
+				value.SetMapIndex(key, changed)
+			}
+			return nil
+		}
+		if entry.Kind() != reflect.Ptr {
+			return fmt.Errorf("%v can't be changed by path", strings.Join(walked, "."))
+		}
+		return setPath(entry, segments[1:], walked, change)
+
+	default:
+		return fmt.Errorf("%v has no field %v", strings.Join(walked[:len(walked)-1], "."), segment)
+	}
+}
+
+// newProblems returns problems reported by after which weren't reported by before
+func newProblems(before, after error) []string {
+	known := map[string]bool{}
+	if validationErr, ok := before.(*ValidationError); ok {
+		for _, problem := range validationErr.Problems {
+			known[problem] = true
+		}
+	}
+	problems := []string{}
+	if validationErr, ok := after.(*ValidationError); ok {
+		for _, problem := range validationErr.Problems {
+			if !known[problem] {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,178 @@
+package issuectl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func crudConfig() IssuectlConfig {
+	return GetConfig(
+		"work",
+		map[RepoConfigName]*RepoConfig{
+			"api":    {Name: "api", Owner: "org", RepoURL: "git@example.com:org/api.git"},
+			"unused": {Name: "unused", Owner: "org", RepoURL: "git@example.com:org/unused.git"},
+		},
+		map[BackendConfigName]*BackendConfig{
+			"gh": {Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{Username: "tester"}},
+		},
+		map[GitUserName]*GitUser{
+			"tester": {Name: "tester", Email: "tester@example.com", SSHKey: "/dev/null"},
+		},
+		map[ProfileName]*Profile{
+			"work": {
+				Name:              "work",
+				WorkDir:           "/work",
+				GitUserName:       "tester",
+				IssueBackend:      "gh",
+				RepoBackend:       "gh",
+				Repositories:      []RepoConfigName{"api"},
+				DefaultRepository: "api",
+			},
+		},
+	).GetInMemory()
+}
+
+// TestSet tests setting config values by path.
+func TestSet(t *testing.T) {
+	config := crudConfig()
+
+	if err := config.Set("profiles.work.workDir", "/other"); err != nil {
+		t.Fatalf("Set() failed: %s", err)
+	}
+	if err := config.Set("profiles.work.repositories", "[api, unused]"); err != nil {
+		t.Fatalf("Set() failed: %s", err)
+	}
+	if err := config.Set("backends.gh.github.host", "https://github.example.com"); err != nil {
+		t.Fatalf("Set() failed: %s", err)
+	}
+	if err := config.Set("repositories.api.owner", "007"); err != nil {
+		t.Fatalf("Set() failed: %s", err)
+	}
+
+	profile := config.GetProfile("work")
+	if profile.WorkDir != "/other" {
+		t.Errorf("expected workDir /other, got %v", profile.WorkDir)
+	}
+	if expected := []RepoConfigName{"api", "unused"}; !reflect.DeepEqual(profile.Repositories, expected) {
+		t.Errorf("expected repositories %v, got %v", expected, profile.Repositories)
+	}
+	if host := config.GetBackend("gh").GitHub.Host; host != "https://github.example.com" {
+		t.Errorf("expected host to be set, got %v", host)
+	}
+	if owner := config.GetRepository("api").Owner; owner != "007" {
+		t.Errorf("expected owner 007, got %v", owner)
+	}
+}
+
+// TestSetErrors tests that invalid paths and values breaking references are rejected.
+func TestSetErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		value    string
+		expected string
+	}{
+		{name: "typo", path: "profiles.work.workdir", value: "/x", expected: `did you mean "workDir"?`},
+		{name: "missing entity", path: "profiles.home.workDir", value: "/x", expected: "profiles.home not defined"},
+		{name: "missing reference", path: "profiles.work.gituser", value: "nobody", expected: "git user nobody which is not defined"},
+		{name: "scalar has no fields", path: "profiles.work.workDir.foo", value: "x", expected: "profiles.work.workDir has no field foo"},
+		{name: "invalid value", path: "gitLab", value: "x", expected: "unknown config field gitLab"},
+		{name: "wrong type", path: "profiles.work.repositories", value: "{a: b}", expected: "invalid value"},
+		{name: "empty segment", path: "profiles..workDir", value: "x", expected: "invalid config path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := crudConfig()
+			err := config.Set(tt.path, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("expected error containing %q, got %v", tt.expected, err)
+			}
+			if config.GetProfile("work").GitUserName != "tester" {
+				t.Errorf("config changed by rejected Set()")
+			}
+		})
+	}
+}
+
+// TestUnset tests removing config values and entities by path.
+func TestUnset(t *testing.T) {
+	config := crudConfig()
+
+	if err := config.Unset("repositories.unused"); err != nil {
+		t.Fatalf("Unset() failed: %s", err)
+	}
+	if config.GetRepository("unused") != nil {
+		t.Errorf("expected repository to be removed")
+	}
+
+	if err := config.Unset("repositories.api"); err == nil {
+		t.Errorf("expected error when removing repository used by profile")
+	}
+
+	if err := config.Unset("backends.gh.github.username"); err != nil {
+		t.Fatalf("Unset() failed: %s", err)
+	}
+	if username := config.GetBackend("gh").GitHub.Username; username != "" {
+		t.Errorf("expected username to be removed, got %v", username)
+	}
+}
+
+// TestDeleteReferencedEntities tests that entities used by profiles and issues can't be deleted.
+func TestDeleteReferencedEntities(t *testing.T) {
+	config := crudConfig()
+	if err := config.AddIssue(&IssueConfig{ID: "1", Profile: "work", Repositories: []RepoConfigName{"api"}}); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		delete   func() error
+		expected string
+	}{
+		{name: "repository", delete: func() error { return config.DeleteRepository("api") }, expected: "repository api is still used by issue 1, profile work"},
+		{name: "backend", delete: func() error { return config.DeleteBackend("gh") }, expected: "backend gh is still used by profile work"},
+		{name: "git user", delete: func() error { return config.DeleteGitUser("tester") }, expected: "git user tester is still used by profile work"},
+		{name: "profile", delete: func() error { return config.DeleteProfile("work") }, expected: "profile work is still used by current profile setting, issue 1"},
+		{name: "missing", delete: func() error { return config.DeleteRepository("missing") }, expected: "repository missing not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.delete()
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if err := config.DeleteRepository("unused"); err != nil {
+		t.Errorf("DeleteRepository() of unused repository failed: %s", err)
+	}
+}
+
+// TestDeleteEntitiesInheritedByProfile tests that entities inherited through extends count as used by child profile.
+func TestDeleteEntitiesInheritedByProfile(t *testing.T) {
+	config := crudConfig()
+	if err := config.AddProfile(&Profile{Name: "child", Extends: "work"}); err != nil {
+		t.Fatalf("AddProfile() failed: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		delete   func() error
+		expected string
+	}{
+		{name: "repository", delete: func() error { return config.DeleteRepository("api") }, expected: "repository api is still used by profile child, profile work"},
+		{name: "backend", delete: func() error { return config.DeleteBackend("gh") }, expected: "backend gh is still used by profile child, profile work"},
+		{name: "git user", delete: func() error { return config.DeleteGitUser("tester") }, expected: "git user tester is still used by profile child, profile work"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.delete()
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -1,182 +1,126 @@
 package issuectl
 
-// import (
-// 	"os"
-// 	"testing"
-
-// 	"github.com/stretchr/testify/assert"
-// )
-
-// func TestIssuectlConfig_Save(t *testing.T) {
-// 	config := &IssuectlConfig{
-// 		urrentProfile: "testProfile",
-// 	}
-
-// 	err := config.Save()
-// 	if err != nil {
-// 		t.Errorf("Expected no error, got %v", err)
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestLoadConfig(t *testing.T) {
-// 	config := &IssuectlConfig{
-// 		currentProfile: "testProfile",
-// 	}
-
-// 	err := config.Save()
-// 	if err != nil {
-// 		t.Errorf("Expected no error, got %v", err)
-// 	}
-
-// 	loadedConfig := LoadConfig()
-// 	if loadedConfig.currentProfile != config.currentProfile {
-// 		t.Errorf("Expected CurrentProfile '%s', got '%s'", config.currentProfile, loadedConfig.currentProfile)
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestIssuectlConfig_AddIssue(t *testing.T) {
-// 	config := &IssuectlConfig{}
-
-// 	issue := &IssueConfig{
-// 		ID: "testIssue",
-// 	}
-
-// 	err := config.AddIssue(issue)
-// 	if err != nil {
-// 		t.Errorf("Expected no error, got %v", err)
-// 	}
-
-// 	if len(config.issues) != 1 {
-// 		t.Errorf("Expected 1 issue, got %d", len(config.issues))
-// 	}
-
-// 	if config.issues[0].ID != issue.ID {
-// 		t.Errorf("Expected issue ID '%s', got '%s'", issue.ID, config.issues[0].ID)
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestIssuectlConfig_DeleteIssue(t *testing.T) {
-// 	config := &IssuectlConfig{}
-
-// 	issue := &IssueConfig{
-// 		ID: "testIssue",
-// 	}
-
-// 	config.AddIssue(issue) //nolint:errcheck
-
-// 	assert.NoError(t, config.DeleteIssue(issue.ID))
-
-// 	if len(config.issues) != 0 {
-// 		t.Errorf("Expected 0 issues, got %d", len(config.issues))
-// 	}
-
-// 	// Test deleting a non-existing issue
-// 	assert.Error(t, config.DeleteIssue("nonExistingIssue"))
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestIssuectlConfig_GetIssue(t *testing.T) {
-// 	config := &IssuectlConfig{}
-
-// 	issue := &IssueConfig{
-// 		ID: "testIssue",
-// 	}
-
-// 	config.AddIssue(issue) //nolint:errcheck
-
-// 	retrievedIssue := config.GetIssue(issue.ID)
-
-// 	if retrievedIssue.ID != issue.ID { //nolint:staticcheck
-// 		t.Errorf("Expected issue ID '%s', got '%s'", issue.ID, retrievedIssue.ID)
-// 	}
-
-// 	// Test getting a non-existing issue
-// 	retrievedIssue = config.GetIssue("nonExistingIssue")
-// 	if retrievedIssue != nil {
-// 		t.Errorf("Expected nil, got issue")
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestIssuectlConfig_ListRepositories(t *testing.T) {
-// 	config := &IssuectlConfig{}
-
-// 	repo := &RepoConfig{
-// 		Name: "testRepo",
-// 	}
-
-// 	config.AddRepository(repo) //nolint:errcheck
-
-// 	err := config.ListRepositories()
-// 	if err != nil {
-// 		t.Errorf("Expected no error, got %v", err)
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestIssuectlConfig_GetRepository(t *testing.T) {
-// 	config := &IssuectlConfig{}
-
-// 	repo := &RepoConfig{
-// 		Name: "testRepo",
-// 	}
-
-// 	config.AddRepository(repo) //nolint:errcheck
-
-// 	retrievedRepo := config.GetRepository(repo.Name)
-// 	if retrievedRepo == nil { //nolint:staticcheck
-// 		t.Errorf("Expected repository, got nil")
-// 	}
-
-// 	if retrievedRepo.Name != repo.Name { //nolint:staticcheck
-// 		t.Errorf("Expected repository name '%s', got '%s'", repo.Name, retrievedRepo.Name)
-// 	}
-
-// 	// Test getting a non-existing repository
-// 	retrievedRepo = config.GetRepository("nonExistingRepo")
-// 	if retrievedRepo != nil {
-// 		t.Errorf("Expected nil, got repository")
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
-
-// func TestIssuectlConfig_AddRepository(t *testing.T) {
-// 	config := &IssuectlConfig{}
-
-// 	repo := &RepoConfig{
-// 		Name: "testRepo",
-// 	}
-
-// 	err := config.AddRepository(repo)
-// 	if err != nil {
-// 		t.Errorf("Expected no error, got %v", err)
-// 	}
-
-// 	if len(config.repositories) != 1 {
-// 		t.Errorf("Expected 1 repository, got %d", len(config.repositories))
-// 	}
-
-// 	if config.repositories[0].Name != repo.Name {
-// 		t.Errorf("Expected repository name '%s', got '%s'", repo.Name, config.repositories[0].Name)
-// 	}
-
-// 	// Clean up
-// 	os.Remove(DefaultConfigFilePath)
-// }
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSaveAndLoadConfig tests that saved config is loaded back from the same path.
+func TestSaveAndLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+	config := GetConfig("testProfile", nil, nil, nil, nil).WithPath(path).GetPersistent()
+	if err := config.Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	loaded := mustLoadConfig(t, path)
+	if loaded.GetCurrentProfile() != "testProfile" {
+		t.Errorf("expected current profile testProfile, got %v", loaded.GetCurrentProfile())
+	}
+	if loaded.GetPath() != path {
+		t.Errorf("expected loaded config path %v, got %v", path, loaded.GetPath())
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("config file not created: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected config file mode 0600, got %v", perm)
+	}
+}
+
+// TestLoadMissingConfig tests that missing config file results in empty config saved to given path.
+func TestLoadMissingConfig(t *testing.T) {
+	path := tempConfigPath(t)
+	config := mustLoadConfig(t, path).GetPersistent()
+	if len(config.GetProfiles()) != 0 {
+		t.Errorf("expected empty config, got %v profiles", len(config.GetProfiles()))
+	}
+
+	if err := config.AddRepository(&RepoConfig{Name: "testRepo"}); err != nil {
+		t.Fatalf("AddRepository() failed: %s", err)
+	}
+	if mustLoadConfig(t, path).GetRepository("testRepo") == nil {
+		t.Errorf("expected repository to be saved to %v", path)
+	}
+}
+
+// TestIssues tests adding, getting and deleting issues.
+func TestIssues(t *testing.T) {
+	path := tempConfigPath(t)
+	config := mustLoadConfig(t, path).GetPersistent()
+
+	if err := config.AddIssue(&IssueConfig{ID: "testIssue"}); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+	if _, found := mustLoadConfig(t, path).GetIssue("testIssue"); !found {
+		t.Errorf("expected issue testIssue in saved config")
+	}
+	if _, found := config.GetIssue("nonExistingIssue"); found {
+		t.Errorf("expected nonExistingIssue not to be found")
+	}
+
+	if err := config.DeleteIssue("testIssue"); err != nil {
+		t.Fatalf("DeleteIssue() failed: %s", err)
+	}
+	if len(mustLoadConfig(t, path).GetIssues()) != 0 {
+		t.Errorf("expected no issues after delete")
+	}
+}
+
+// TestResolveConfigPath tests precedence of config locations.
+func TestResolveConfigPath(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv(EnvConfigPath, "")
+
+	xdgPath := filepath.Join(xdg, "issuectl", "config.yaml")
+	legacyPath := filepath.Join(home, ".issuerc")
+
+	resolve := func() string {
+		t.Helper()
+		path, err := ResolveConfigPath("")
+		if err != nil {
+			t.Fatalf("ResolveConfigPath() failed: %s", err)
+		}
+		return path
+	}
+
+	if path := resolve(); path != xdgPath {
+		t.Errorf("expected XDG path %v for fresh install, got %v", xdgPath, path)
+	}
+
+	if err := os.WriteFile(legacyPath, []byte("version: 1\n"), 0600); err != nil {
+		t.Fatalf("failed to write legacy config: %s", err)
+	}
+	if path := resolve(); path != legacyPath {
+		t.Errorf("expected existing legacy path %v, got %v", legacyPath, path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(xdgPath), 0700); err != nil {
+		t.Fatalf("failed to create config dir: %s", err)
+	}
+	if err := os.WriteFile(xdgPath, []byte("version: 1\n"), 0600); err != nil {
+		t.Fatalf("failed to write XDG config: %s", err)
+	}
+	if path := resolve(); path != xdgPath {
+		t.Errorf("expected XDG path %v to take precedence over legacy, got %v", xdgPath, path)
+	}
+
+	t.Setenv(EnvConfigPath, "/env/config.yaml")
+	if path := resolve(); path != "/env/config.yaml" {
+		t.Errorf("expected path from %v, got %v", EnvConfigPath, path)
+	}
+
+	path, err := ResolveConfigPath("/flag/config.yaml")
+	if err != nil {
+		t.Fatalf("ResolveConfigPath() failed: %s", err)
+	}
+	if path != "/flag/config.yaml" {
+		t.Errorf("expected explicit path to take precedence, got %v", path)
+	}
+}
@@ -0,0 +1,157 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrIssueNotDetected is returned when directory doesn't belong to any issue
+var ErrIssueNotDetected = errors.New("can't detect issue from current directory, provide issue ID")
+
+// DetectIssue finds issue directory dir belongs to. Directories of issues from all profiles are checked,
+// when none of them contains dir, issue is matched with git branch checked out in dir.
+func DetectIssue(ctx context.Context, config IssuectlConfig, dir string) (*IssueConfig, error) {
+	if issue := issueByDir(config.GetIssues(), dir); issue != nil {
+		Log.V(5).Infof("Detected issue %v from directory %v", issue.ID, dir)
+		return issue, nil
+	}
+
+	branch, err := currentGitClient(config).CurrentBranch(ctx, dir)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		Log.V(5).Infof("Can't read current branch in %v: %v", dir, err)
+		return nil, ErrIssueNotDetected
+	}
+	if issue := issueByBranch(config.GetIssues(), branch); issue != nil {
+		Log.V(5).Infof("Detected issue %v from branch %v", issue.ID, branch)
+		return issue, nil
+	}
+	return nil, ErrIssueNotDetected
+}
+
+// IssueStatus describes issue directory belongs to, it's printed in shell prompt
+type IssueStatus struct {
+	ID      IssueID     `yaml:"id"`
+	Name    string      `yaml:"name"`
+	Profile ProfileName `yaml:"profile"`
+	// Branch is checked out in directory, empty outside of git repository
+	Branch string `yaml:"branch"`
+	// Dirty tells if git repository has uncommitted changes
+	Dirty bool `yaml:"dirty"`
+}
+
+// GetIssueStatus returns status of issue dir belongs to, it only reads local state so it's fast enough for prompt
+func GetIssueStatus(ctx context.Context, config IssuectlConfig, dir string) (*IssueStatus, error) {
+	issue, err := DetectIssue(ctx, config, dir)
+	if err != nil {
+		return nil, err
+	}
+	status := &IssueStatus{ID: issue.ID, Name: issue.Name, Profile: issue.Profile}
+	git, err := profileGitClient(config.GetProfile(issue.Profile))
+	if err != nil {
+		return nil, err
+	}
+	if branch, err := git.CurrentBranch(ctx, dir); err == nil {
+		status.Branch = branch
+		if status.Dirty, err = git.HasChanges(ctx, dir); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// IssuePath returns directory of issue or, when repo is given, of issue repository
+func IssuePath(config IssuectlConfig, issueID IssueID, repo RepoConfigName) (string, error) {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return "", fmt.Errorf("issue %v not found", issueID)
+	}
+	if repo == "" {
+		return issue.Dir, nil
+	}
+	for _, name := range issue.Repositories {
+		if name == repo {
+			return filepath.Join(issue.Dir, string(repo)), nil
+		}
+	}
+	return "", fmt.Errorf("repository %v isn't part of issue %v", repo, issueID)
+}
+
+// ResolveIssueID returns issue with given ID or, when ID is empty, issue detected from dir
+func ResolveIssueID(ctx context.Context, config IssuectlConfig, issueID IssueID, dir string) (IssueID, error) {
+	if issueID != "" {
+		return issueID, nil
+	}
+	issue, err := DetectIssue(ctx, config, dir)
+	if err != nil {
+		return "", err
+	}
+	return issue.ID, nil
+}
+
+// issueByDir returns issue with the deepest directory containing dir
+func issueByDir(issues map[IssueID]*IssueConfig, dir string) *IssueConfig {
+	dir = canonicalPath(dir)
+	var found *IssueConfig
+	foundDir := ""
+	for _, id := range SortedKeys(issues) {
+		issue := issues[id]
+		if issue.Dir == "" {
+			continue
+		}
+		issueDir := canonicalPath(issue.Dir)
+		if !isWithin(issueDir, dir) || len(issueDir) <= len(foundDir) {
+			continue
+		}
+		found, foundDir = issue, issueDir
+	}
+	return found
+}
+
+// issueByBranch returns issue using branch, or issue with the longest ID branch name starts with,
+// e.g. XY-69 for XY-69-fix-login or feature/XY-69-fix-login
+func issueByBranch(issues map[IssueID]*IssueConfig, branch string) *IssueConfig {
+	for _, id := range SortedKeys(issues) {
+		if issues[id].BranchName == branch {
+			return issues[id]
+		}
+	}
+
+	name := branch[strings.LastIndex(branch, "/")+1:]
+	var found *IssueConfig
+	for _, id := range SortedKeys(issues) {
+		prefix := string(id)
+		if name != prefix && !strings.HasPrefix(name, prefix+"-") && !strings.HasPrefix(name, prefix+"_") {
+			continue
+		}
+		if found == nil || len(prefix) > len(found.ID) {
+			found = issues[id]
+		}
+	}
+	return found
+}
+
+// canonicalPath returns absolute path with symlinks resolved, or cleaned path when it can't be resolved
+func canonicalPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// isWithin checks if path is base or is inside of it
+func isWithin(base, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,95 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDetectIssue tests detecting issue from directories of all profiles and from checked out branch.
+func TestDetectIssue(t *testing.T) {
+	root := t.TempDir()
+	fixture := newGitFixture(t, "api")
+	clone := filepath.Join(root, "elsewhere", "api")
+	runGitFixture(t, root, "clone", fixture.URL, clone)
+	runGitFixture(t, clone, "checkout", "-b", "feature/XY-69-fix-login")
+
+	issues := map[IssueID]*IssueConfig{
+		"XY-6":  {ID: "XY-6", Name: "other", Dir: filepath.Join(root, "work", "custom-name"), Profile: "work"},
+		"XY-69": {ID: "XY-69", Name: "login", BranchName: "XY-69-fix-login", Dir: filepath.Join(root, "work", "XY-69"), Profile: "work"},
+		"44":    {ID: "44", Name: "priv", Dir: filepath.Join(root, "priv", "44"), Profile: "priv"},
+	}
+	for _, issue := range issues {
+		if err := os.MkdirAll(filepath.Join(issue.Dir, "repo"), 0755); err != nil {
+			t.Fatalf("failed to create issue dir: %s", err)
+		}
+	}
+	config := GetConfig("work", nil, nil, nil, nil).GetInMemory()
+	for _, issue := range issues {
+		if err := config.AddIssue(issue); err != nil {
+			t.Fatalf("AddIssue() failed: %s", err)
+		}
+	}
+
+	tests := []struct {
+		dir      string
+		expected IssueID
+	}{
+		{filepath.Join(root, "work", "custom-name", "repo"), "XY-6"},
+		{filepath.Join(root, "priv", "44"), "44"},
+		{clone, "XY-69"},
+	}
+	for _, test := range tests {
+		issue, err := DetectIssue(context.Background(), config, test.dir)
+		if err != nil {
+			t.Errorf("DetectIssue(%v) failed: %s", test.dir, err)
+			continue
+		}
+		if issue.ID != test.expected {
+			t.Errorf("DetectIssue(%v) expected %v, got %v", test.dir, test.expected, issue.ID)
+		}
+	}
+
+	if _, err := DetectIssue(context.Background(), config, filepath.Join(root, "work")); !errors.Is(err, ErrIssueNotDetected) {
+		t.Errorf("expected ErrIssueNotDetected outside of issue dirs, got %v", err)
+	}
+	if id, _ := ResolveIssueID(context.Background(), config, "44", clone); id != "44" {
+		t.Errorf("expected explicit issue ID to be used, got %v", id)
+	}
+}
+
+// TestIssueStatusAndPath tests local status of issue printed in prompt and paths used by cd.
+func TestIssueStatusAndPath(t *testing.T) {
+	root := t.TempDir()
+	fixture := newGitFixture(t, "api")
+	issue := &IssueConfig{ID: "44", Name: "44-fix", Dir: root, Repositories: []RepoConfigName{"api"}, Profile: "priv"}
+	repoDir := filepath.Join(root, "api")
+	runGitFixture(t, root, "clone", fixture.URL, repoDir)
+	config := GetConfig("priv", nil, nil, nil, nil).GetInMemory()
+	if err := config.AddIssue(issue); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+
+	status, err := GetIssueStatus(context.Background(), config, repoDir)
+	if err != nil {
+		t.Fatalf("GetIssueStatus() failed: %s", err)
+	}
+	if status.ID != "44" || status.Branch != "master" || status.Dirty {
+		t.Errorf("unexpected status of clean repository %+v", status)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "new.txt"), []byte("change"), 0644); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+	if status, err = GetIssueStatus(context.Background(), config, repoDir); err != nil || !status.Dirty {
+		t.Errorf("expected dirty status after change, got %+v, %v", status, err)
+	}
+
+	if path, err := IssuePath(config, "44", "api"); err != nil || path != repoDir {
+		t.Errorf("IssuePath() expected %v, got %v, %v", repoDir, path, err)
+	}
+	if _, err := IssuePath(config, "44", "web"); err == nil {
+		t.Errorf("expected error for repository outside of issue")
+	}
+}
@@ -0,0 +1,111 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// DiagnosticResult is a result of single doctor check, Err is nil when check passed
+type DiagnosticResult struct {
+	Check string
+	Err   error
+	// Skipped is reason why check didn't apply, empty when it ran
+	Skipped string
+}
+
+// Diagnose checks config and everything in environment issuectl depends on:
+// git binary, SSH keys, profile work directories and backend credentials.
+func Diagnose(ctx context.Context, config IssuectlConfig) []DiagnosticResult {
+	results := []DiagnosticResult{
+		{Check: "config references are valid", Err: config.Validate()},
+		{Check: "git is available", Err: checkGit(ctx)},
+	}
+
+	users := config.GetGitUsers()
+	for _, name := range SortedKeys(users) {
+		result := DiagnosticResult{Check: fmt.Sprintf("SSH key of git user %v exists", name)}
+		if users[name].SSHKey == "" {
+			// users without SSH key authenticate over HTTPS
+			result.Skipped = "no SSH key set"
+		} else {
+			result.Err = checkSSHKey(users[name])
+		}
+		results = append(results, result)
+	}
+
+	profiles := config.GetProfiles()
+	for _, name := range SortedKeys(profiles) {
+		results = append(results, DiagnosticResult{
+			Check: fmt.Sprintf("workdir of profile %v is writable", name),
+			Err:   checkWritableDir(profiles[name].WorkDir),
+		})
+	}
+
+	backends := config.GetBackends()
+	for _, name := range SortedKeys(backends) {
+		err := VerifyBackendCredentials(ctx, backends[name])
+		if err != nil {
+			err = fmt.Errorf("credentials rejected: %w", err)
+		}
+		results = append(results, DiagnosticResult{
+			Check: fmt.Sprintf("credentials of backend %v are valid", name),
+			Err:   err,
+		})
+		results = append(results, DiagnosticResult{
+			Check: fmt.Sprintf("token of backend %v isn't stored in config file", name),
+			Err:   checkTokenStorage(backends[name]),
+		})
+	}
+
+	return results
+}
+
+func checkTokenStorage(backend *BackendConfig) error {
+	if backend.TokenSource == nil && backend.HasStoredToken() {
+		return fmt.Errorf("token is only base64 encoded, run `issuectl config backend migrate-secrets` or configure token source")
+	}
+	return nil
+}
+
+func checkGit(ctx context.Context) error {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return fmt.Errorf("git not found in PATH")
+	}
+	if err := runCommand(ctx, exec.CommandContext(ctx, path, "--version")); err != nil {
+		return fmt.Errorf("failed to run %v: %w", path, err)
+	}
+	return nil
+}
+
+func checkSSHKey(user *GitUser) error {
+	info, err := os.Stat(user.SSHKey)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%v is a directory", user.SSHKey)
+	}
+	return nil
+}
+
+func checkWritableDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("workdir not set")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%v is not a directory", dir)
+	}
+	file, err := os.CreateTemp(dir, ".issuectl-doctor-*")
+	if err != nil {
+		return fmt.Errorf("%v is not writable: %w", dir, err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
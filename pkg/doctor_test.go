@@ -0,0 +1,28 @@
+package issuectl
+
+import (
+	"context"
+	"testing"
+)
+
+// TestDiagnoseSkipsUsersWithoutSSHKey tests that git users without SSH key aren't reported as problems.
+func TestDiagnoseSkipsUsersWithoutSSHKey(t *testing.T) {
+	config := GetEmptyConfig().GetInMemory()
+	if err := config.AddGitUser(&GitUser{Name: "https", Email: "https@example.com"}); err != nil {
+		t.Fatalf("AddGitUser() failed: %s", err)
+	}
+	if err := config.AddGitUser(&GitUser{Name: "ssh", Email: "ssh@example.com", SSHKey: "/nonexistent/id_rsa"}); err != nil {
+		t.Fatalf("AddGitUser() failed: %s", err)
+	}
+
+	results := map[string]DiagnosticResult{}
+	for _, result := range Diagnose(context.Background(), config) {
+		results[result.Check] = result
+	}
+	if result := results["SSH key of git user https exists"]; result.Skipped == "" || result.Err != nil {
+		t.Errorf("expected check of user without SSH key to be skipped, got %+v", result)
+	}
+	if result := results["SSH key of git user ssh exists"]; result.Skipped != "" || result.Err == nil {
+		t.Errorf("expected missing SSH key to be reported, got %+v", result)
+	}
+}
@@ -0,0 +1,192 @@
+package issuectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ExecOptions control how command is run across repositories of issue
+type ExecOptions struct {
+	// Parallel runs command in all repositories at once instead of one after another
+	Parallel bool
+	// Group prints output of each repository at once after command finishes there,
+	// instead of lines prefixed with repository name as they come
+	Group bool
+}
+
+// ExecFailure is repository command failed in
+type ExecFailure struct {
+	Repository RepoConfigName
+	Err        error
+}
+
+// ExecError aggregates failures of command run across repositories of issue
+type ExecError struct {
+	Failures []ExecFailure
+	Total    int
+}
+
+func (e *ExecError) Error() string {
+	failures := []string{}
+	for _, failure := range e.Failures {
+		failures = append(failures, fmt.Sprintf("%v (%v)", failure.Repository, failure.Err))
+	}
+	return fmt.Sprintf("command failed in %v of %v repositories: %v", len(e.Failures), e.Total, strings.Join(failures, ", "))
+}
+
+// ExitCode is highest exit code of failed commands, 1 when command couldn't be started
+func (e *ExecError) ExitCode() int {
+	code := 1
+	for _, failure := range e.Failures {
+		var exitErr *exec.ExitError
+		if errors.As(failure.Err, &exitErr) && exitErr.ExitCode() > code {
+			code = exitErr.ExitCode()
+		}
+	}
+	return code
+}
+
+// ExecInIssue runs command in directory of every repository of issue, writing output to out.
+// Single argument is run with sh -c, so it can use pipes and globs. Returns *ExecError when command fails in any repository.
+func ExecInIssue(ctx context.Context, config IssuectlConfig, issueID IssueID, command []string, opts ExecOptions, out io.Writer) error {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return fmt.Errorf("issue %v not found", issueID)
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("command to run not given")
+	}
+	if len(issue.Repositories) == 0 {
+		return fmt.Errorf("issue %v has no repositories", issueID)
+	}
+	if len(command) == 1 {
+		command = []string{"sh", "-c", command[0]}
+	}
+
+	width := 0
+	for _, repo := range issue.Repositories {
+		if len(repo) > width {
+			width = len(repo)
+		}
+	}
+
+	// output of repositories run in parallel is written under lock, so lines don't mix
+	var outMu sync.Mutex
+	run := func(repo RepoConfigName) error {
+		dir := filepath.Join(issue.Dir, string(repo))
+		if !fileExists(dir) {
+			return fmt.Errorf("directory %v doesn't exist", dir)
+		}
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"ISSUECTL_ISSUE_ID="+string(issue.ID),
+			"ISSUECTL_REPO="+string(repo),
+			"ISSUECTL_REPO_DIR="+dir,
+		)
+
+		if opts.Group {
+			var output bytes.Buffer
+			cmd.Stdout = &output
+			cmd.Stderr = &output
+			err := runCommand(ctx, cmd)
+
+			outMu.Lock()
+			defer outMu.Unlock()
+			fmt.Fprintf(out, "==> %v <==\n", repo)
+			_, _ = out.Write(output.Bytes())
+			if output.Len() > 0 && !bytes.HasSuffix(output.Bytes(), []byte("\n")) {
+				fmt.Fprintln(out)
+			}
+			if err != nil {
+				fmt.Fprintf(out, "==> %v failed: %v\n", repo, err)
+			}
+			fmt.Fprintln(out)
+			return err
+		}
+
+		writer := &prefixWriter{out: out, mu: &outMu, prefix: fmt.Sprintf("%-*v | ", width, repo)}
+		cmd.Stdout = writer
+		cmd.Stderr = writer
+		err := runCommand(ctx, cmd)
+		writer.Flush()
+		return err
+	}
+
+	Log.V(3).Infof("Running %v in %v repositories", strings.Join(command, " "), len(issue.Repositories))
+	errs := make([]error, len(issue.Repositories))
+	if opts.Parallel {
+		var wg sync.WaitGroup
+		for i, repo := range issue.Repositories {
+			wg.Add(1)
+			go func(i int, repo RepoConfigName) {
+				defer wg.Done()
+				errs[i] = run(repo)
+			}(i, repo)
+		}
+		wg.Wait()
+	} else {
+		for i, repo := range issue.Repositories {
+			if errs[i] = run(repo); ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	execErr := &ExecError{Total: len(issue.Repositories)}
+	for i, err := range errs {
+		if err != nil {
+			execErr.Failures = append(execErr.Failures, ExecFailure{Repository: issue.Repositories[i], Err: err})
+		}
+	}
+	if len(execErr.Failures) > 0 {
+		return execErr
+	}
+	return nil
+}
+
+// prefixWriter writes complete lines prefixed with name of repository they come from
+type prefixWriter struct {
+	out     io.Writer
+	mu      *sync.Mutex
+	prefix  string
+	partial []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	lines := bytes.SplitAfter(w.partial, []byte("\n"))
+	// last element is incomplete line, empty when data ended with newline
+	w.partial = append([]byte{}, lines[len(lines)-1]...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, line := range lines[:len(lines)-1] {
+		if _, err := io.WriteString(w.out, w.prefix+string(line)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes line not terminated with newline
+func (w *prefixWriter) Flush() {
+	if len(w.partial) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, _ = io.WriteString(w.out, w.prefix+string(w.partial)+"\n")
+	w.partial = nil
+}
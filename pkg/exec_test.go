@@ -0,0 +1,71 @@
+package issuectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func startExecTest(t *testing.T) *testEnv {
+	t.Helper()
+	env := newTestEnv(t, nil, nil, "api", "web-frontend")
+	if err := StartWorkingOnIssue(context.Background(), "", env.config, "13"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	return env
+}
+
+// TestExecInIssue tests prefixed and grouped output of command run in every repository.
+func TestExecInIssue(t *testing.T) {
+	env := startExecTest(t)
+	tests := []struct {
+		opts     ExecOptions
+		expected []string
+	}{
+		{ExecOptions{}, []string{"api          | # api\n", "web-frontend | # web-frontend\n"}},
+		{ExecOptions{Parallel: true}, []string{"api          | # api\n", "web-frontend | # web-frontend\n"}},
+		{ExecOptions{Group: true}, []string{"==> api <==\n# api\n", "==> web-frontend <==\n# web-frontend\n"}},
+		{ExecOptions{Parallel: true, Group: true}, []string{"==> api <==\n# api\n", "==> web-frontend <==\n# web-frontend\n"}},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := ExecInIssue(context.Background(), env.config, "13", []string{"cat", "README.md"}, test.opts, &out); err != nil {
+			t.Errorf("ExecInIssue(%+v) failed: %s", test.opts, err)
+			continue
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("ExecInIssue(%+v) output doesn't contain %q:\n%s", test.opts, expected, out.String())
+			}
+		}
+	}
+}
+
+// TestExecInIssueFailure tests that failures are aggregated and the highest exit code is reported.
+func TestExecInIssueFailure(t *testing.T) {
+	env := startExecTest(t)
+	issue, _ := env.config.GetIssue("13")
+	if err := os.WriteFile(filepath.Join(issue.Dir, "api", "partial"), []byte("no newline"), 0644); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	var out bytes.Buffer
+	err := ExecInIssue(context.Background(), env.config, "13", []string{"cat partial && exit 2 || exit 4"}, ExecOptions{}, &out)
+	var execErr *ExecError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("expected ExecError, got %v", err)
+	}
+	if len(execErr.Failures) != 2 || execErr.Total != 2 {
+		t.Errorf("expected 2 of 2 failures, got %v of %v", len(execErr.Failures), execErr.Total)
+	}
+	if execErr.ExitCode() != 4 {
+		t.Errorf("expected exit code 4, got %v", execErr.ExitCode())
+	}
+	if !strings.Contains(out.String(), "api          | no newline\n") {
+		t.Errorf("expected unterminated line to be flushed, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,454 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Implementations of GitClient selectable with gitClient of profile
+const (
+	// GitClientExec runs git binary
+	GitClientExec = "exec"
+	// GitClientGoGit uses pure Go implementation, it works without git installed
+	GitClientGoGit = "go-git"
+)
+
+// GitClient performs git operations issuectl needs in repositories of issues
+type GitClient interface {
+	// Clone clones url into dir
+	Clone(ctx context.Context, url RepoURL, dir string, opts CloneOptions) error
+	// GitDir returns .git directory of repository in dir, shared by its worktrees
+	GitDir(ctx context.Context, dir string) (string, error)
+	// GetConfig returns value of config option, empty when it isn't set, key is section.name, e.g. user.email
+	GetConfig(ctx context.Context, dir, key string) (string, error)
+	// SetConfig sets local config option of repository, key is section.name, e.g. user.email
+	SetConfig(ctx context.Context, dir, key, value string) error
+	// BranchExists checks if local branch exists
+	BranchExists(ctx context.Context, dir, branch string) (bool, error)
+	// CurrentBranch returns checked out branch, HEAD when it's detached
+	CurrentBranch(ctx context.Context, dir string) (string, error)
+	// Upstream returns upstream of checked out branch, e.g. origin/main, empty when branch has none
+	Upstream(ctx context.Context, dir string) (string, error)
+	// RefExists checks if ref, e.g. origin/main, can be resolved
+	RefExists(ctx context.Context, dir, ref string) (bool, error)
+	// Compare counts commits HEAD is ahead and behind ref
+	Compare(ctx context.Context, dir, ref string) (*BranchComparison, error)
+	// Checkout switches to branch, creating it with opts.Create
+	Checkout(ctx context.Context, dir, branch string, opts CheckoutOptions) error
+	// Fetch fetches origin
+	Fetch(ctx context.Context, dir string, user *GitUser) error
+	// Push pushes branch to origin and sets it as upstream
+	Push(ctx context.Context, dir, branch string, opts PushOptions) error
+	// HasChanges checks if repository has uncommitted changes
+	HasChanges(ctx context.Context, dir string) (bool, error)
+	// ChangedFiles lists uncommitted changes in format of git status --porcelain
+	ChangedFiles(ctx context.Context, dir string) ([]string, error)
+	// HasStagedChanges checks if there are changes to commit
+	HasStagedChanges(ctx context.Context, dir string) (bool, error)
+	// AddAll stages every change, including new and deleted files
+	AddAll(ctx context.Context, dir string) error
+	// Commit commits staged changes with identity set in repository config
+	Commit(ctx context.Context, dir, message string) error
+	// LastCommit returns commit checked out in dir
+	LastCommit(ctx context.Context, dir string) (*CommitInfo, error)
+}
+
+// GitSyncer rebases and merges branches, implemented by git clients supporting it
+type GitSyncer interface {
+	// Rebase rebases checked out branch onto ref
+	Rebase(ctx context.Context, dir, ref string) error
+	// Merge merges ref into checked out branch
+	Merge(ctx context.Context, dir, ref string) error
+	// SyncInProgress returns rebase or merge when one was stopped, empty string otherwise
+	SyncInProgress(ctx context.Context, dir string) (string, error)
+	// ContinueSync continues stopped rebase or merge, keeping prepared commit messages
+	ContinueSync(ctx context.Context, dir, operation string) error
+	// ConflictedFiles lists unmerged files
+	ConflictedFiles(ctx context.Context, dir string) ([]string, error)
+}
+
+// CloneOptions control how repository is cloned
+type CloneOptions struct {
+	// User's SSH key is used for SSH remotes when implementation can't rely on ssh config
+	User *GitUser
+	// Depth limits history to given number of commits, 0 clones all of it
+	Depth int
+}
+
+// CheckoutOptions control how branch is checked out
+type CheckoutOptions struct {
+	// Create creates new branch instead of switching to existing one
+	Create bool
+	// StartPoint is ref new branch is created at, e.g. origin/main, defaults to HEAD
+	StartPoint string
+}
+
+// PushOptions control how branch is pushed
+type PushOptions struct {
+	// User's SSH key is used for SSH remotes when implementation can't rely on ssh config
+	User *GitUser
+	// ForceWithLease allows rewritten branch to be pushed as long as remote didn't change
+	ForceWithLease bool
+}
+
+// CommitInfo identifies commit
+type CommitInfo struct {
+	// Hash is abbreviated
+	Hash    string
+	Subject string
+	When    time.Time
+}
+
+// String formats commit as hash, subject and how long ago it was made
+func (c *CommitInfo) String() string {
+	return fmt.Sprintf("%v %v (%v)", c.Hash, c.Subject, timeAgo(c.When))
+}
+
+// timeAgo describes how long ago t was, e.g. 3 hours ago
+func timeAgo(t time.Time) string {
+	elapsed := time.Since(t)
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		if count := int(elapsed / unit.duration); count > 0 {
+			if count == 1 {
+				return fmt.Sprintf("1 %v ago", unit.name)
+			}
+			return fmt.Sprintf("%v %vs ago", count, unit.name)
+		}
+	}
+	return "just now"
+}
+
+// errNotSupported is returned by operations git client can't do
+func errNotSupported(client, operation string) error {
+	return fmt.Errorf("%v is not supported by %v git client, set gitClient of profile to %v", operation, client, GitClientExec)
+}
+
+// gitSyncer returns git client as GitSyncer or error when it doesn't support sync
+func gitSyncer(client GitClient) (GitSyncer, error) {
+	syncer, ok := client.(GitSyncer)
+	if !ok {
+		return nil, errNotSupported(GitClientGoGit, "sync")
+	}
+	return syncer, nil
+}
+
+// validateGitClient checks that git client implementation is known
+func validateGitClient(name string) error {
+	_, err := NewGitClient(name)
+	return err
+}
+
+// NewGitClient returns git client implementation selected by name [defaults to exec]
+func NewGitClient(name string) (GitClient, error) {
+	switch name {
+	case "", GitClientExec:
+		return execGit{}, nil
+	case GitClientGoGit:
+		return goGit{}, nil
+	}
+	return nil, fmt.Errorf("unsupported gitClient %q, use %v or %v", name, GitClientExec, GitClientGoGit)
+}
+
+// profileGitClient returns git client selected in profile, profile can be nil
+func profileGitClient(profile *Profile) (GitClient, error) {
+	if profile == nil {
+		return NewGitClient("")
+	}
+	return NewGitClient(profile.GitClient)
+}
+
+// currentGitClient returns git client of current profile, it's used before issue and its profile are known
+func currentGitClient(config IssuectlConfig) GitClient {
+	client, err := profileGitClient(config.GetProfile(config.GetCurrentProfile()))
+	if err != nil {
+		return execGit{}
+	}
+	return client
+}
+
+// GitError is failure of git command with its error output
+type GitError struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *GitError) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("git %v failed: %v", e.Args[0], e.Stderr)
+	}
+	return fmt.Sprintf("git %v failed: %v", e.Args[0], e.Err)
+}
+
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// gitOutput runs git command in dir and returns its output without trailing newlines.
+// Leading whitespace is kept, it's significant in output like git status --porcelain.
+// Failures are returned as *GitError with error output of git.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	Log.V(5).Infof("git %v", strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		gitErr := &GitError{Args: args, Err: err}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			gitErr.Stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return "", gitErr
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// execGit runs git binary
+type execGit struct{}
+
+func (execGit) Clone(ctx context.Context, url RepoURL, dir string, opts CloneOptions) error {
+	args := []string{"clone", "--quiet"}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	args = append(args, string(url), dir)
+	Log.V(3).Infof("git %v", strings.Join(args, " "))
+	_, err := gitOutput(ctx, "", args...)
+	return err
+}
+
+func (execGit) GitDir(ctx context.Context, dir string) (string, error) {
+	gitDir, err := gitOutput(ctx, dir, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	return gitDir, nil
+}
+
+func (execGit) GetConfig(ctx context.Context, dir, key string) (string, error) {
+	value, err := gitOutput(ctx, dir, "config", "--get", key)
+	// git config exits with 1 when option isn't set
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	return value, err
+}
+
+func (execGit) SetConfig(ctx context.Context, dir, key, value string) error {
+	Log.V(3).Infof("git config %v %v", key, value)
+	_, err := gitOutput(ctx, dir, "config", key, value)
+	return err
+}
+
+func (execGit) BranchExists(ctx context.Context, dir, branch string) (bool, error) {
+	output, err := gitOutput(ctx, dir, "branch", "--list", branch)
+	return output != "", err
+}
+
+func (execGit) CurrentBranch(ctx context.Context, dir string) (string, error) {
+	return gitOutput(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+func (execGit) Upstream(ctx context.Context, dir string) (string, error) {
+	// branch without upstream isn't an error, it just wasn't pushed yet
+	upstream, err := gitOutput(ctx, dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", nil
+	}
+	return upstream, nil
+}
+
+func (execGit) RefExists(ctx context.Context, dir, ref string) (bool, error) {
+	if _, err := gitOutput(ctx, dir, "rev-parse", "--verify", "--quiet", ref); err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (execGit) Compare(ctx context.Context, dir, ref string) (*BranchComparison, error) {
+	output, err := gitOutput(ctx, dir, "rev-list", "--left-right", "--count", ref+"...HEAD")
+	if err != nil {
+		return nil, err
+	}
+	counts := strings.Fields(output)
+	if len(counts) != 2 {
+		return nil, fmt.Errorf("unexpected output of git rev-list: %q", output)
+	}
+	behind, err := strconv.Atoi(counts[0])
+	if err != nil {
+		return nil, err
+	}
+	ahead, err := strconv.Atoi(counts[1])
+	if err != nil {
+		return nil, err
+	}
+	return &BranchComparison{Ref: ref, Ahead: ahead, Behind: behind}, nil
+}
+
+func (execGit) Checkout(ctx context.Context, dir, branch string, opts CheckoutOptions) error {
+	args := []string{"checkout", "--quiet", branch}
+	if opts.Create {
+		args = []string{"checkout", "--quiet", "-b", branch}
+		if opts.StartPoint != "" {
+			args = append(args, opts.StartPoint)
+		}
+	}
+	Log.V(3).Infof("git %v", strings.Join(args, " "))
+	_, err := gitOutput(ctx, dir, args...)
+	return err
+}
+
+// Fetch relies on core.sshCommand set by setRepoIdentity to use SSH key of user
+func (execGit) Fetch(ctx context.Context, dir string, user *GitUser) error {
+	Log.V(3).Infof("git fetch origin")
+	_, err := gitOutput(ctx, dir, "fetch", "--quiet", "origin")
+	return err
+}
+
+// Push relies on core.sshCommand set by setRepoIdentity to use SSH key of user
+func (execGit) Push(ctx context.Context, dir, branch string, opts PushOptions) error {
+	args := []string{"push", "--quiet", "--set-upstream", "origin", branch}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease")
+	}
+	Log.V(3).Infof("git %v", strings.Join(args, " "))
+	_, err := gitOutput(ctx, dir, args...)
+	return err
+}
+
+func (g execGit) HasChanges(ctx context.Context, dir string) (bool, error) {
+	changes, err := g.ChangedFiles(ctx, dir)
+	return len(changes) > 0, err
+}
+
+func (execGit) ChangedFiles(ctx context.Context, dir string) ([]string, error) {
+	output, err := gitOutput(ctx, dir, "status", "--porcelain")
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+func (execGit) HasStagedChanges(ctx context.Context, dir string) (bool, error) {
+	output, err := gitOutput(ctx, dir, "diff", "--cached", "--name-only")
+	return output != "", err
+}
+
+func (execGit) AddAll(ctx context.Context, dir string) error {
+	Log.V(3).Infof("git add --all")
+	_, err := gitOutput(ctx, dir, "add", "--all")
+	return err
+}
+
+func (execGit) Commit(ctx context.Context, dir, message string) error {
+	Log.V(3).Infof("git commit -m %q", message)
+	_, err := gitOutput(ctx, dir, "commit", "--quiet", "-m", message)
+	return err
+}
+
+func (execGit) LastCommit(ctx context.Context, dir string) (*CommitInfo, error) {
+	output, err := gitOutput(ctx, dir, "log", "-1", "--format=%h%x00%ct%x00%s")
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.SplitN(output, "\x00", 3)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected output of git log: %q", output)
+	}
+	timestamp, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitInfo{Hash: fields[0], Subject: fields[2], When: time.Unix(timestamp, 0)}, nil
+}
+
+func (execGit) Rebase(ctx context.Context, dir, ref string) error {
+	Log.V(3).Infof("git rebase %v", ref)
+	_, err := gitOutput(ctx, dir, "rebase", ref)
+	return err
+}
+
+func (execGit) Merge(ctx context.Context, dir, ref string) error {
+	Log.V(3).Infof("git merge --no-edit %v", ref)
+	_, err := gitOutput(ctx, dir, "merge", "--no-edit", ref)
+	return err
+}
+
+func (execGit) SyncInProgress(ctx context.Context, dir string) (string, error) {
+	markers := []struct {
+		operation string
+		path      string
+	}{
+		{SyncRebase, "rebase-merge"},
+		{SyncRebase, "rebase-apply"},
+		{SyncMerge, "MERGE_HEAD"},
+	}
+	for _, marker := range markers {
+		path, err := gitOutput(ctx, dir, "rev-parse", "--git-path", marker.path)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if fileExists(path) {
+			return marker.operation, nil
+		}
+	}
+	return "", nil
+}
+
+func (execGit) ContinueSync(ctx context.Context, dir, operation string) error {
+	Log.V(5).Infof("git %v --continue", operation)
+	cmd := exec.CommandContext(ctx, "git", operation, "--continue")
+	cmd.Dir = dir
+	// editor would wait for confirmation of commit message
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// git reports why it can't continue on both stdout and stderr
+		return &GitError{Args: []string{operation, "--continue"}, Stderr: strings.TrimSpace(string(output)), Err: err}
+	}
+	return nil
+}
+
+func (execGit) ConflictedFiles(ctx context.Context, dir string) ([]string, error) {
+	output, err := gitOutput(ctx, dir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\n"), nil
+}
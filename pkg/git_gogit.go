@@ -0,0 +1,420 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// goGit implements git operations in Go, so issuectl works where git isn't installed.
+// It doesn't implement GitSyncer, go-git can't rebase or merge.
+type goGit struct{}
+
+// open opens repository containing dir
+func (goGit) open(dir string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+}
+
+// auth returns SSH key of user for SSH remotes, other remotes use default authentication
+func (goGit) auth(url string, user *GitUser) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Protocol != "ssh" || user == nil || user.SSHKey == "" {
+		return nil, nil
+	}
+	sshUser := endpoint.User
+	if sshUser == "" {
+		sshUser = "git"
+	}
+	return gitssh.NewPublicKeysFromFile(sshUser, user.SSHKey, "")
+}
+
+// remoteAuth returns authentication for origin of repo
+func (g goGit) remoteAuth(repo *git.Repository, user *GitUser) (transport.AuthMethod, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return nil, err
+	}
+	return g.auth(remote.Config().URLs[0], user)
+}
+
+func (g goGit) Clone(ctx context.Context, url RepoURL, dir string, opts CloneOptions) error {
+	Log.V(3).Infof("go-git clone %v %v", url, dir)
+	auth, err := g.auth(string(url), opts.User)
+	if err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	cloneOptions := &git.CloneOptions{URL: string(url), Auth: auth, Depth: opts.Depth}
+	if _, err := git.PlainCloneContext(ctx, dir, false, cloneOptions); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	return nil
+}
+
+func (g goGit) GitDir(ctx context.Context, dir string) (string, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return "", fmt.Errorf("repository in %v isn't stored in .git directory", dir)
+	}
+	return storage.Filesystem().Root(), nil
+}
+
+func (g goGit) GetConfig(ctx context.Context, dir, key string) (string, error) {
+	section, option, found := strings.Cut(key, ".")
+	if !found {
+		return "", fmt.Errorf("git config failed: key %v has no section", key)
+	}
+	repo, err := g.open(dir)
+	if err != nil {
+		return "", fmt.Errorf("git config failed: %w", err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("git config failed: %w", err)
+	}
+	return cfg.Raw.Section(section).Option(option), nil
+}
+
+func (g goGit) SetConfig(ctx context.Context, dir, key, value string) error {
+	Log.V(3).Infof("go-git config %v %v", key, value)
+	section, option, found := strings.Cut(key, ".")
+	if !found {
+		return fmt.Errorf("git config failed: key %v has no section", key)
+	}
+	repo, err := g.open(dir)
+	if err != nil {
+		return fmt.Errorf("git config failed: %w", err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("git config failed: %w", err)
+	}
+	cfg.Raw.Section(section).SetOption(option, value)
+	if err := repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("git config failed: %w", err)
+	}
+	return nil
+}
+
+func (g goGit) BranchExists(ctx context.Context, dir, branch string) (bool, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return false, fmt.Errorf("git branch failed: %w", err)
+	}
+	_, err = repo.Reference(plumbing.NewBranchReferenceName(branch), false)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git branch failed: %w", err)
+	}
+	return true, nil
+}
+
+func (g goGit) CurrentBranch(ctx context.Context, dir string) (string, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "HEAD", nil
+	}
+	return head.Name().Short(), nil
+}
+
+func (g goGit) Upstream(ctx context.Context, dir string) (string, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	branch, found := cfg.Branches[head.Name().Short()]
+	if !head.Name().IsBranch() || !found || branch.Remote == "" || branch.Merge == "" {
+		return "", nil
+	}
+	return branch.Remote + "/" + branch.Merge.Short(), nil
+}
+
+func (g goGit) RefExists(ctx context.Context, dir, ref string) (bool, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	_, err = repo.ResolveRevision(plumbing.Revision(ref))
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return true, nil
+}
+
+func (g goGit) Compare(ctx context.Context, dir, ref string) (*BranchComparison, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	refHash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %v: %w", ref, err)
+	}
+	headCommits, err := g.ancestors(repo, head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	refCommits, err := g.ancestors(repo, *refHash)
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+
+	comparison := &BranchComparison{Ref: ref}
+	for hash := range headCommits {
+		if !refCommits[hash] {
+			comparison.Ahead++
+		}
+	}
+	for hash := range refCommits {
+		if !headCommits[hash] {
+			comparison.Behind++
+		}
+	}
+	return comparison, nil
+}
+
+// ancestors returns commit with hash and all commits reachable from it
+func (goGit) ancestors(repo *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	commits := map[plumbing.Hash]bool{}
+	err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(c *object.Commit) error {
+		commits[c.Hash] = true
+		return nil
+	})
+	return commits, err
+}
+
+func (g goGit) Checkout(ctx context.Context, dir, branch string, opts CheckoutOptions) error {
+	Log.V(3).Infof("go-git checkout %v", branch)
+	repo, err := g.open(dir)
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	// like git checkout, uncommitted changes are kept
+	options := &git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: opts.Create, Keep: true}
+	if opts.Create && opts.StartPoint != "" {
+		hash, err := repo.ResolveRevision(plumbing.Revision(opts.StartPoint))
+		if err != nil {
+			return fmt.Errorf("git checkout failed: %v: %w", opts.StartPoint, err)
+		}
+		options.Hash = *hash
+	}
+	if err := worktree.Checkout(options); err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	return nil
+}
+
+func (g goGit) Fetch(ctx context.Context, dir string, user *GitUser) error {
+	Log.V(3).Infof("go-git fetch origin")
+	repo, err := g.open(dir)
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+	auth, err := g.remoteAuth(repo, user)
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+	err = repo.FetchContext(ctx, &git.FetchOptions{RemoteName: "origin", Auth: auth})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+	return nil
+}
+
+func (g goGit) Push(ctx context.Context, dir, branch string, opts PushOptions) error {
+	Log.V(3).Infof("go-git push --set-upstream origin %v", branch)
+	repo, err := g.open(dir)
+	if err != nil {
+		return fmt.Errorf("git push failed: %w", err)
+	}
+	auth, err := g.remoteAuth(repo, opts.User)
+	if err != nil {
+		return fmt.Errorf("git push failed: %w", err)
+	}
+
+	ref := plumbing.NewBranchReferenceName(branch)
+	pushOptions := &git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(ref + ":" + ref)},
+		Auth:       auth,
+	}
+	if opts.ForceWithLease {
+		// lease is remote-tracking branch, like with git push --force-with-lease
+		pushOptions.ForceWithLease = &git.ForceWithLease{}
+	}
+	err = repo.PushContext(ctx, pushOptions)
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("git push failed: %w", err)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("git push failed: %w", err)
+	}
+	cfg.Branches[branch] = &gitconfig.Branch{Name: branch, Remote: "origin", Merge: ref}
+	if err := repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("git push failed: %w", err)
+	}
+	return nil
+}
+
+// status returns status of worktree of repository in dir
+func (g goGit) status(dir string) (git.Status, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	return status, nil
+}
+
+func (g goGit) HasChanges(ctx context.Context, dir string) (bool, error) {
+	status, err := g.status(dir)
+	if err != nil {
+		return false, err
+	}
+	return !status.IsClean(), nil
+}
+
+func (g goGit) ChangedFiles(ctx context.Context, dir string) ([]string, error) {
+	status, err := g.status(dir)
+	if err != nil {
+		return nil, err
+	}
+	changes := []string{}
+	for _, path := range SortedKeys(status) {
+		file := status[path]
+		if file.Staging == git.Unmodified && file.Worktree == git.Unmodified {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%c%c %s", file.Staging, file.Worktree, path))
+	}
+	return changes, nil
+}
+
+func (g goGit) HasStagedChanges(ctx context.Context, dir string) (bool, error) {
+	status, err := g.status(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range status {
+		if file.Staging != git.Unmodified && file.Staging != git.Untracked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (g goGit) AddAll(ctx context.Context, dir string) error {
+	Log.V(3).Infof("go-git add --all")
+	repo, err := g.open(dir)
+	if err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+	return nil
+}
+
+// Commit doesn't run git hooks, go-git doesn't support them
+func (g goGit) Commit(ctx context.Context, dir, message string) error {
+	Log.V(3).Infof("go-git commit -m %q", message)
+	repo, err := g.open(dir)
+	if err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	// author is read from user.name and user.email of repository config
+	if _, err := worktree.Commit(message, &git.CommitOptions{}); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	return nil
+}
+
+func (g goGit) LastCommit(ctx context.Context, dir string) (*CommitInfo, error) {
+	repo, err := g.open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	subject, _, _ := strings.Cut(commit.Message, "\n")
+	return &CommitInfo{Hash: commit.Hash.String()[:7], Subject: subject, When: commit.Committer.When}, nil
+}
@@ -0,0 +1,403 @@
+package issuectl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Backend tests replay HTTP interactions stored in testdata/fixtures/<backend>/<name>.json.
+// Requests are matched by method and path, fields of fixture request body have to be present in the request.
+// To refresh fixture against real API run tests with:
+//
+//	ISSUECTL_RECORD=1 ISSUECTL_RECORD_TOKEN=<token> go test ./pkg/ -run <test>
+//
+// Requests are then proxied to fixture upstream (or ISSUECTL_RECORD_UPSTREAM) and saved back to fixture file.
+
+const (
+	envRecord         = "ISSUECTL_RECORD"
+	envRecordToken    = "ISSUECTL_RECORD_TOKEN"
+	envRecordUpstream = "ISSUECTL_RECORD_UPSTREAM"
+	fixtureToken      = "test-token"
+)
+
+type fixtureRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type fixtureResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type fixtureInteraction struct {
+	Request  fixtureRequest  `json:"request"`
+	Response fixtureResponse `json:"response"`
+}
+
+type apiFixture struct {
+	Upstream     string                `json:"upstream"`
+	Interactions []*fixtureInteraction `json:"interactions"`
+}
+
+// apiServer serves recorded API responses from in-process HTTP server
+type apiServer struct {
+	*httptest.Server
+
+	t         *testing.T
+	path      string
+	fixture   *apiFixture
+	recording bool
+
+	mu   sync.Mutex
+	used map[*fixtureInteraction]bool
+}
+
+func isRecording() bool {
+	return os.Getenv(envRecord) != ""
+}
+
+// getFixtureToken returns token backends should use with apiServer
+func getFixtureToken() string {
+	if isRecording() {
+		return os.Getenv(envRecordToken)
+	}
+	return fixtureToken
+}
+
+// newAPIServer starts server replaying (or recording) fixture testdata/fixtures/<name>.json
+func newAPIServer(t *testing.T, name string) *apiServer {
+	t.Helper()
+	s := &apiServer{
+		t:         t,
+		path:      filepath.Join("testdata", "fixtures", name+".json"),
+		fixture:   &apiFixture{},
+		recording: isRecording(),
+		used:      map[*fixtureInteraction]bool{},
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil && !(s.recording && os.IsNotExist(err)) {
+		t.Fatalf("failed to read fixture %v: %v", s.path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, s.fixture); err != nil {
+			t.Fatalf("failed to parse fixture %v: %v", s.path, err)
+		}
+	}
+
+	if s.recording {
+		if upstream := os.Getenv(envRecordUpstream); upstream != "" {
+			s.fixture.Upstream = upstream
+		}
+		s.fixture.Interactions = nil
+		s.Server = httptest.NewServer(http.HandlerFunc(s.record))
+	} else {
+		s.Server = httptest.NewServer(http.HandlerFunc(s.replay))
+	}
+
+	t.Cleanup(func() {
+		s.Close()
+		if s.recording {
+			s.save()
+			return
+		}
+		for _, interaction := range s.fixture.Interactions {
+			if !s.used[interaction] {
+				t.Errorf("%v: expected request %v %v wasn't made", s.path, interaction.Request.Method, interaction.Request.Path)
+			}
+		}
+	})
+	return s
+}
+
+func (s *apiServer) replay(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	for _, interaction := range s.fixture.Interactions {
+		if s.used[interaction] || interaction.Request.Method != r.Method || interaction.Request.Path != r.URL.Path {
+			continue
+		}
+		if len(interaction.Request.Body) > 0 && !jsonContains(interaction.Request.Body, body) {
+			s.t.Errorf("%v: unexpected body for %v %v:\n got: %s\nwant: %s",
+				s.path, r.Method, r.URL.Path, body, interaction.Request.Body)
+		}
+		s.used[interaction] = true
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(interaction.Response.Status)
+		_, _ = w.Write(interaction.Response.Body)
+		return
+	}
+
+	s.t.Errorf("%v: unexpected request %v %v", s.path, r.Method, r.URL.Path)
+	http.Error(w, "unexpected request", http.StatusNotImplemented)
+}
+
+func (s *apiServer) record(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, strings.TrimSuffix(s.fixture.Upstream, "/")+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.Header = r.Header.Clone()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	interaction := &fixtureInteraction{
+		Request:  fixtureRequest{Method: r.Method, Path: r.URL.Path},
+		Response: fixtureResponse{Status: resp.StatusCode},
+	}
+	if json.Valid(body) {
+		interaction.Request.Body = body
+	}
+	if json.Valid(respBody) {
+		interaction.Response.Body = respBody
+	}
+
+	s.mu.Lock()
+	s.fixture.Interactions = append(s.fixture.Interactions, interaction)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+func (s *apiServer) save() {
+	data, err := json.MarshalIndent(s.fixture, "", "  ")
+	if err != nil {
+		s.t.Errorf("failed to encode fixture %v: %v", s.path, err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		s.t.Errorf("failed to save fixture %v: %v", s.path, err)
+		return
+	}
+	if err := os.WriteFile(s.path, append(data, '\n'), 0644); err != nil {
+		s.t.Errorf("failed to save fixture %v: %v", s.path, err)
+	}
+}
+
+// jsonContains checks if JSON document got contains all fields from want
+func jsonContains(want, got []byte) bool {
+	var wantValue, gotValue interface{}
+	if json.Unmarshal(want, &wantValue) != nil || json.Unmarshal(got, &gotValue) != nil {
+		return false
+	}
+	return valueContains(wantValue, gotValue)
+}
+
+func valueContains(want, got interface{}) bool {
+	wantMap, ok := want.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(want, got)
+	}
+	gotMap, ok := got.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, value := range wantMap {
+		if !valueContains(value, gotMap[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// gitFixture is a local bare repository with single commit on master used instead of remote repositories
+type gitFixture struct {
+	// URL of bare repository, usable as RepoConfig.RepoURL
+	URL string
+	dir string
+}
+
+// newGitFixture creates bare repository with initial commit in temporary directory
+func newGitFixture(t *testing.T, name string) *gitFixture {
+	t.Helper()
+	root := t.TempDir()
+	bare := filepath.Join(root, name+".git")
+	work := filepath.Join(root, name)
+
+	runGitFixture(t, root, "init", "--bare", "--initial-branch=master", bare)
+	runGitFixture(t, root, "clone", bare, work)
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# "+name+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %v", err)
+	}
+	runGitFixture(t, work, "add", "README.md")
+	runGitFixture(t, work, "commit", "-m", "Initial commit")
+	runGitFixture(t, work, "push", "origin", "HEAD:master")
+
+	return &gitFixture{URL: bare, dir: bare}
+}
+
+// commit pushes commit with files to branch of fixture repository, branch is created from master when missing
+func (f *gitFixture) commit(t *testing.T, branch string, files map[string]string) {
+	t.Helper()
+	work := filepath.Join(t.TempDir(), "work")
+	runGitFixture(t, filepath.Dir(work), "clone", f.URL, work)
+	if branch != "master" {
+		runGitFixture(t, work, "checkout", "-B", branch)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(work, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write fixture file: %v", err)
+		}
+	}
+	runGitFixture(t, work, "add", "--all")
+	runGitFixture(t, work, "commit", "--allow-empty", "-m", "Update "+branch)
+	runGitFixture(t, work, "push", "--force", "origin", "HEAD:"+branch)
+}
+
+// hasBranch checks if branch was pushed to fixture repository
+func (f *gitFixture) hasBranch(t *testing.T, branch string) bool {
+	t.Helper()
+	cmd := exec.Command("git", "branch", "--list", branch)
+	cmd.Dir = f.dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git branch --list %v failed: %v", branch, err)
+	}
+	return len(out) > 0
+}
+
+func runGitFixture(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=issuectl", "-c", "user.email=issuectl@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// testEnv holds in-memory config with git fixtures and backends pointing to apiServer
+type testEnv struct {
+	config  IssuectlConfig
+	repos   map[RepoConfigName]*gitFixture
+	workDir string
+}
+
+// newTestEnv prepares profile "test" using given backends and git fixture per repository
+func newTestEnv(t *testing.T, issueBackend, repoBackend *BackendConfig, repos ...RepoConfigName) *testEnv {
+	t.Helper()
+	pullRequestLinkDelay = 0
+
+	env := &testEnv{
+		config:  GetEmptyConfig().GetInMemory(),
+		repos:   map[RepoConfigName]*gitFixture{},
+		workDir: t.TempDir(),
+	}
+	mustNoErr := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to prepare config: %v", err)
+		}
+	}
+
+	for _, name := range repos {
+		fixture := newGitFixture(t, string(name))
+		env.repos[name] = fixture
+		mustNoErr(env.config.AddRepository(&RepoConfig{Name: name, Owner: "owner", RepoURL: RepoURL(fixture.URL)}))
+	}
+	mustNoErr(env.config.AddGitUser(&GitUser{Name: "tester", Email: "tester@example.com", SSHKey: "/dev/null"}))
+
+	profile := &Profile{
+		Name:              "test",
+		WorkDir:           env.workDir,
+		GitUserName:       "tester",
+		Repositories:      repos,
+		DefaultRepository: repos[0],
+	}
+	for _, backend := range []*BackendConfig{issueBackend, repoBackend} {
+		if backend != nil {
+			mustNoErr(env.config.AddBackend(backend))
+		}
+	}
+	if issueBackend != nil {
+		profile.IssueBackend = issueBackend.Name
+	}
+	if repoBackend != nil {
+		profile.RepoBackend = repoBackend.Name
+	}
+	mustNoErr(env.config.AddProfile(profile))
+	mustNoErr(env.config.UseProfile(profile.Name))
+	return env
+}
+
+// updateProfile changes test profile with update and stores it
+func (env *testEnv) updateProfile(t *testing.T, update func(profile *Profile)) {
+	t.Helper()
+	profile := env.config.GetProfile("test")
+	update(profile)
+	if err := env.config.UpdateProfile(profile); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+}
+
+// setGitClient selects git client of test profile
+func (env *testEnv) setGitClient(t *testing.T, name string) {
+	t.Helper()
+	env.updateProfile(t, func(profile *Profile) { profile.GitClient = name })
+}
+
+func encodeFixtureToken() string {
+	return base64.RawStdEncoding.EncodeToString([]byte(getFixtureToken()))
+}
+
+func githubFixtureBackend(server *apiServer) *BackendConfig {
+	return &BackendConfig{
+		Name: "github",
+		Type: BackendGithub,
+		GitHub: &GitHubConfig{
+			Host:     server.URL,
+			Token:    encodeFixtureToken(),
+			Username: "tester",
+		},
+	}
+}
+
+func gitlabFixtureBackend(server *apiServer) *BackendConfig {
+	return &BackendConfig{
+		Name: "gitlab",
+		Type: BackendGitLab,
+		GitLab: &GitLabConfig{
+			Host:   server.URL,
+			Token:  encodeFixtureToken(),
+			UserID: 7,
+		},
+	}
+}
+
+func jiraFixtureBackend(server *apiServer) *BackendConfig {
+	return &BackendConfig{
+		Name: "jira",
+		Type: BackendJira,
+		Jira: &JiraConfig{
+			Host:     server.URL,
+			Token:    encodeFixtureToken(),
+			Username: "tester@example.com",
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package issuectl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// HookEvent is a moment in issue lifecycle hooks are run at
+type HookEvent string
+
+const (
+	HookPreStart    HookEvent = "preStart"
+	HookPostStart   HookEvent = "postStart"
+	HookPreAddRepo  HookEvent = "preAddRepo"
+	HookPostAddRepo HookEvent = "postAddRepo"
+	HookPreOpenPR   HookEvent = "preOpenPR"
+	HookPostOpenPR  HookEvent = "postOpenPR"
+	HookPreFinish   HookEvent = "preFinish"
+	HookPostFinish  HookEvent = "postFinish"
+)
+
+var hookEvents = []HookEvent{
+	HookPreStart, HookPostStart, HookPreAddRepo, HookPostAddRepo,
+	HookPreOpenPR, HookPostOpenPR, HookPreFinish, HookPostFinish,
+}
+
+// Failure policies of hooks
+const (
+	// HookAbort stops the command when hook fails
+	HookAbort = "abort"
+	// HookWarn logs failure of hook and continues
+	HookWarn = "warn"
+)
+
+// Hook is a shell command run at issue lifecycle event
+type Hook struct {
+	// Run is a command executed with sh -c
+	Run string `yaml:"run"`
+	// OnFailure is abort or warn [defaults to abort]
+	OnFailure string `yaml:"onFailure,omitempty"`
+}
+
+// Hooks lists hooks run at each event
+type Hooks map[HookEvent][]Hook
+
+// validateHooks checks events, commands and failure policies of hooks
+func validateHooks(hooks Hooks) []string {
+	problems := []string{}
+	for _, event := range SortedKeys(hooks) {
+		known := false
+		for _, hookEvent := range hookEvents {
+			known = known || hookEvent == event
+		}
+		if !known {
+			problems = append(problems, fmt.Sprintf("has hooks for unknown event %v", event))
+		}
+		for i, hook := range hooks[event] {
+			if strings.TrimSpace(hook.Run) == "" {
+				problems = append(problems, fmt.Sprintf("has %v hook %v without command", event, i+1))
+			}
+			if hook.OnFailure != "" && hook.OnFailure != HookAbort && hook.OnFailure != HookWarn {
+				problems = append(problems, fmt.Sprintf(
+					"has %v hook %v with invalid onFailure %q, use %v or %v", event, i+1, hook.OnFailure, HookAbort, HookWarn,
+				))
+			}
+		}
+	}
+	return problems
+}
+
+// runIssueHooks runs hooks of profile in issue directory, followed by hooks of each repository in repository directory.
+// Directories which don't exist, before cloning or after cleanup, are replaced by issue directory or profile workDir.
+func runIssueHooks(
+	ctx context.Context,
+	config IssuectlConfig,
+	event HookEvent,
+	profile *Profile,
+	issue *IssueConfig,
+	repositories []RepoConfigName,
+) error {
+	env := hookEnv(event, profile, issue)
+	fallbackDirs := []string{issue.Dir, profile.WorkDir}
+
+	for _, hook := range profile.Hooks[event] {
+		if err := runHook(ctx, event, "profile "+string(profile.Name), hook, existingDir(fallbackDirs...), env); err != nil {
+			return err
+		}
+	}
+
+	for _, repoName := range repositories {
+		repo := config.GetRepository(repoName)
+		if repo == nil {
+			continue
+		}
+		repoDir := filepath.Join(issue.Dir, string(repoName))
+		repoEnv := append(append([]string{}, env...), "ISSUECTL_REPO="+string(repoName), "ISSUECTL_REPO_DIR="+repoDir)
+		hooks, err := repositoryHooks(repo, repoDir, event)
+		if err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			dir := existingDir(append([]string{repoDir}, fallbackDirs...)...)
+			if err := runHook(ctx, event, "repository "+string(repoName), hook, dir, repoEnv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// repositoryHooks returns hooks of repository for event followed by ones from .issuectl.yaml of its clone in dir,
+// when repository allows them. Clone doesn't exist yet before start and addRepo, so only config hooks run then.
+func repositoryHooks(repo *RepoConfig, dir string, event HookEvent) ([]Hook, error) {
+	hooks := repo.Hooks[event]
+	if !fileExists(dir) {
+		return hooks, nil
+	}
+	project, err := LoadProjectConfig(dir)
+	if err != nil || project == nil || len(project.Hooks[event]) == 0 {
+		return hooks, err
+	}
+	if !repo.AllowProjectHooks {
+		Log.V(2).Infof("Skipping %v hooks of %v in %v, allowProjectHooks of repository isn't set", event, ProjectConfigFileName, repo.Name)
+		return hooks, nil
+	}
+	return append(append([]Hook{}, hooks...), project.Hooks[event]...), nil
+}
+
+// hookEnv exposes issue metadata to hooks
+func hookEnv(event HookEvent, profile *Profile, issue *IssueConfig) []string {
+	repos := []string{}
+	for _, repo := range issue.Repositories {
+		repos = append(repos, string(repo))
+	}
+	return append(os.Environ(),
+		"ISSUECTL_HOOK="+string(event),
+		"ISSUECTL_ISSUE_ID="+string(issue.ID),
+		"ISSUECTL_ISSUE_NAME="+issue.Name,
+		"ISSUECTL_ISSUE_DIR="+issue.Dir,
+		"ISSUECTL_BRANCH="+issue.BranchName,
+		"ISSUECTL_REPOSITORIES="+strings.Join(repos, " "),
+		"ISSUECTL_PROFILE="+string(profile.Name),
+		"ISSUECTL_WORKDIR="+profile.WorkDir,
+	)
+}
+
+// runHook runs hook in dir, logging its output and applying its failure policy
+func runHook(ctx context.Context, event HookEvent, owner string, hook Hook, dir string, env []string) error {
+	Log.Infofp("🪝", "Running %v hook of %v: %v", event, owner, hook.Run)
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Run)
+	cmd.Dir = dir
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		Log.Infof("        %v", scanner.Text())
+	}
+
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if hook.OnFailure == HookWarn {
+		Log.Infofp("⚠️", "%v hook of %v failed, continuing: %v", event, owner, err)
+		return nil
+	}
+	return fmt.Errorf("%v hook of %v failed: %w", event, owner, err)
+}
+
+// existingDir returns first of dirs which exists
+func existingDir(dirs ...string) string {
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHooks tests that profile and repository hooks run at lifecycle events in expected directories.
+func TestHooks(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service", "web")
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	record := Hook{Run: `echo "$ISSUECTL_HOOK $ISSUECTL_ISSUE_ID ${ISSUECTL_REPO:-profile} $(basename "$PWD")" >> ` + logPath}
+
+	env.updateProfile(t, func(profile *Profile) {
+		profile.Repositories = []RepoConfigName{"service"}
+		profile.Hooks = Hooks{HookPreStart: {record}, HookPostFinish: {record}}
+	})
+	env.config.GetRepository("service").Hooks = Hooks{HookPostStart: {record}, HookPreFinish: {record}}
+	env.config.GetRepository("web").Hooks = Hooks{HookPreAddRepo: {record}, HookPostAddRepo: {record}}
+
+	ctx := context.Background()
+	if err := StartWorkingOnIssue(ctx, "", env.config, "7"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	if err := AddRepoToIssue(ctx, env.config, "web", "7"); err != nil {
+		t.Fatalf("AddRepoToIssue() failed: %s", err)
+	}
+	if err := FinishWorkingOnIssue(ctx, env.config, "7"); err != nil {
+		t.Fatalf("FinishWorkingOnIssue() failed: %s", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("hooks didn't run: %s", err)
+	}
+	workDir := filepath.Base(env.workDir)
+	expected := []string{
+		"preStart 7 profile 7",
+		"postStart 7 service service",
+		"preAddRepo 7 web 7",
+		"postAddRepo 7 web web",
+		"preFinish 7 service service",
+		"postFinish 7 profile " + workDir,
+	}
+	if got := strings.Split(strings.TrimSpace(string(data)), "\n"); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected hooks\n%v\ngot\n%v", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+// TestHookFailurePolicy tests that failing hook aborts start unless it only warns.
+func TestHookFailurePolicy(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	env.updateProfile(t, func(profile *Profile) {
+		profile.Hooks = Hooks{HookPostStart: {{Run: "exit 3", OnFailure: HookWarn}}}
+	})
+	if err := StartWorkingOnIssue(context.Background(), "", env.config, "1"); err != nil {
+		t.Fatalf("expected start to continue after warning hook, got %s", err)
+	}
+
+	env.updateProfile(t, func(profile *Profile) { profile.Hooks = Hooks{HookPostStart: {{Run: "exit 3"}}} })
+	err := StartWorkingOnIssue(context.Background(), "", env.config, "2")
+	if err == nil || !strings.Contains(err.Error(), "postStart hook of profile test failed") {
+		t.Fatalf("expected failing hook to abort start, got %v", err)
+	}
+	if _, found := env.config.GetIssue("2"); found {
+		t.Errorf("aborted issue saved in config")
+	}
+	if _, err := os.Stat(filepath.Join(env.workDir, "2")); !os.IsNotExist(err) {
+		t.Errorf("expected workdir of aborted issue to be removed")
+	}
+
+	if problems := validateHooks(Hooks{"preLunch": {{Run: "true", OnFailure: "ignore"}}}); len(problems) != 2 {
+		t.Errorf("expected unknown event and invalid policy to be reported, got %v", problems)
+	}
+}
+
+// TestFailingPostAddRepoHookKeepsRepository tests that repository cloned before failing postAddRepo hook is saved in issue.
+func TestFailingPostAddRepoHookKeepsRepository(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service", "web")
+	env.updateProfile(t, func(profile *Profile) { profile.Repositories = []RepoConfigName{"service"} })
+	env.config.GetRepository("web").Hooks = Hooks{HookPostAddRepo: {{Run: "exit 3"}}}
+
+	ctx := context.Background()
+	if err := StartWorkingOnIssue(ctx, "", env.config, "7"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	path := tempConfigPath(t)
+	config := env.config.WithPath(path).GetPersistent()
+	if err := config.Save(); err != nil {
+		t.Fatalf("failed to save config: %s", err)
+	}
+
+	if err := AddRepoToIssue(ctx, config, "web", "7"); err == nil {
+		t.Fatalf("expected failing postAddRepo hook to return error")
+	}
+	issue, _ := mustLoadConfig(t, path).GetIssue("7")
+	if !containsRepository(issue.Repositories, "web") {
+		t.Errorf("expected cloned repository to be saved in issue, got %v", issue.Repositories)
+	}
+}
+
+// TestProjectHooks tests that hooks from .issuectl.yaml of clone run only for repositories allowing them.
+func TestProjectHooks(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	env.repos["service"].commit(t, "master", map[string]string{
+		ProjectConfigFileName: "hooks:\n  postStart:\n    - run: touch project-hook-ran\n",
+	})
+	ctx := context.Background()
+
+	if err := StartWorkingOnIssue(ctx, "", env.config, "1"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ := env.config.GetIssue("1")
+	if fileExists(filepath.Join(issue.Dir, "service", "project-hook-ran")) {
+		t.Errorf("project hook ran without allowProjectHooks")
+	}
+
+	env.config.GetRepository("service").AllowProjectHooks = true
+	if err := StartWorkingOnIssue(ctx, "", env.config, "2"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ = env.config.GetIssue("2")
+	if !fileExists(filepath.Join(issue.Dir, "service", "project-hook-ran")) {
+		t.Errorf("project hook didn't run with allowProjectHooks")
+	}
+}
@@ -1,15 +1,14 @@
 package issuectl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/andygrunwald/go-jira"
-	"github.com/google/go-github/github"
 )
 
 const (
@@ -18,11 +17,43 @@ const (
 	errIssueDoesNotExistOnBackend = "while looking for issue on %v: %v"
 	errFailedToCloseIssue         = "failed to close the issue: %w"
 	errFailedToGetIssue           = "failed to get the issue: %w"
+
+	defaultBaseBranch     = "master"
+	defaultBranchTemplate = "{{.ID}}-{{.Title}}"
+	defaultPRTemplate     = "Resolves #{{.ID}} ✅"
+	defaultCommitTemplate = "{{.ID}}: {{.Message}}"
 )
 
-// StartWorkingOnIssue starts work on an issue
-func StartWorkingOnIssue(customIssueName string, config IssuectlConfig, issueID IssueID) error {
+// pullRequestLinkDelay is time given to backend to make new PR available in API
+var pullRequestLinkDelay = time.Second * 2
+
+// StartWorkingOnIssue starts work on an issue with current profile
+func StartWorkingOnIssue(ctx context.Context, customIssueName string, config IssuectlConfig, issueID IssueID) error {
 	profile := config.GetProfile(config.GetCurrentProfile())
+	if profile == nil {
+		return fmt.Errorf("profile %v not defined", config.GetCurrentProfile())
+	}
+	return StartWorkingOnIssueWithProfile(ctx, customIssueName, config, profile, issueID)
+}
+
+// StartWorkingOnIssueWithProfile starts work on an issue with given profile, e.g. copy of stored profile
+// with CLI overrides applied. Profile itself isn't saved to config, only the new issue is.
+func StartWorkingOnIssueWithProfile(ctx context.Context, customIssueName string, config IssuectlConfig, profile *Profile, issueID IssueID) error {
+	if err := ValidateProfile(config, profile); err != nil {
+		return err
+	}
+	profile, err := EffectiveProfile(profile, currentProjectDir())
+	if err != nil {
+		return err
+	}
+	support, err := CheckOperationSupport(config, profile, OperationStart)
+	if err != nil {
+		return err
+	}
+	if err := validateTemplateDir(profile.TemplateDir); err != nil {
+		return err
+	}
+
 	repositories := []string{}
 	for _, repoName := range profile.Repositories {
 		repositories = append(repositories, string(repoName))
@@ -41,13 +72,19 @@ func StartWorkingOnIssue(customIssueName string, config IssuectlConfig, issueID
 	dirName := name
 	branchName := name
 
-	if profile.IssueBackend != "" && customIssueName == "" {
+	details := &IssueDetails{}
+	if support.Has(CapabilityIssueDetails) && (customIssueName == "" || profile.TemplateDir != "") {
 		backendConfig := config.GetBackend(profile.IssueBackend)
-		issueBackend, err := getIssueBackendConfigurator(backendConfig)
+		issueBackend, err := getIssueBackendConfigurator(ctx, backendConfig)
 		if err != nil {
 			return err
 		}
-		generatedBranchName, err := getBranchName(config, issueBackend, profile, issueID)
+		if details, err = getIssueDetails(ctx, config, issueBackend, profile, issueID); err != nil {
+			return err
+		}
+	}
+	if support.Has(CapabilityIssueDetails) && customIssueName == "" {
+		generatedBranchName, err := getBranchName(profile, issueID, details.Title)
 		if err != nil {
 			return err
 		}
@@ -59,25 +96,74 @@ func StartWorkingOnIssue(customIssueName string, config IssuectlConfig, issueID
 		return err
 	}
 
+	// Don't leave half prepared workspace behind on failure or interrupt
+	completed := false
+	defer func() {
+		if !completed {
+			Log.Infofp("🧹", "Cleaning up issue workdir")
+			if err := os.RemoveAll(issueDirPath); err != nil {
+				Log.Infof("Failed to clean up %v: %v", issueDirPath, err)
+			}
+		}
+	}()
+
+	plannedIssue := &IssueConfig{
+		Name:         branchName,
+		ID:           issueID,
+		BranchName:   branchName,
+		Dir:          issueDirPath,
+		Profile:      profile.Name,
+		Repositories: profile.Repositories,
+	}
+	if err := runIssueHooks(ctx, config, HookPreStart, profile, plannedIssue, profile.Repositories); err != nil {
+		return err
+	}
+
 	Log.Infofp("🛬", "Cloning repositories %v", repositories)
 
-	newIssue, err := createAndAddRepositoriesToIssue(config, profile, issueID, issueDirPath, branchName, branchName, repositories)
+	newIssue, err := createAndAddRepositoriesToIssue(ctx, config, profile, issueID, issueDirPath, branchName, branchName, repositories)
 	if err != nil {
 		return err
 	}
 
-	if profile.IssueBackend != "" {
+	// template is rendered after cloning so that repositories aren't cloned into directories created by it
+	if profile.TemplateDir != "" {
+		Log.Infofp("📝", "Rendering workspace template %v", profile.TemplateDir)
+		data := workspaceTemplateData{
+			ID:          issueID,
+			Name:        plannedIssue.Name,
+			Title:       details.Title,
+			Description: details.Description,
+			URL:         details.URL,
+			Branch:      branchName,
+			Dir:         issueDirPath,
+			Profile:     profile.Name,
+		}
+		data.Repositories = append(data.Repositories, repositories...)
+		if err := renderWorkspaceTemplate(profile.TemplateDir, issueDirPath, data); err != nil {
+			return err
+		}
+	}
+
+	if err := runIssueHooks(ctx, config, HookPostStart, profile, newIssue, newIssue.Repositories); err != nil {
+		return err
+	}
+
+	if support.Has(CapabilityIssueTracking) {
 		Log.Infofp("🫡", "Marking issue as In Progress in %v", profile.IssueBackend)
 
 		// FIXME: this is a workaround for github. we should move this to backend
-		issueRepo := config.GetRepository(profile.DefaultRepository)
+		issueRepo, err := defaultRepository(config, profile)
+		if err != nil {
+			return err
+		}
 
 		backendConfig := config.GetBackend(profile.IssueBackend)
-		issueBackend, err := getIssueBackendConfigurator(backendConfig)
+		issueBackend, err := getIssueBackendConfigurator(ctx, backendConfig)
 		if err != nil {
 			return err
 		}
-		if err := issueBackend.StartIssue(issueRepo.Owner, issueRepo.Name, issueID); err != nil {
+		if err := issueBackend.StartIssue(ctx, issueRepo.Owner, issueRepo.Name, issueID); err != nil {
 			return err
 		}
 	}
@@ -85,26 +171,55 @@ func StartWorkingOnIssue(customIssueName string, config IssuectlConfig, issueID
 	if err := config.AddIssue(newIssue); err != nil {
 		return err
 	}
+	completed = true
 
 	Log.Infofp("🚀", "Workspace for %v ready!", issueID)
-	Log.Infofp("🧑‍💻", "Run `issuectl workon %v` to open it in VS Code", issueID)
+	Log.Infofp("🧑‍💻", "Run `issuectl workon %v` to open it with %v", issueID, IssueLauncher(config, newIssue).Command)
 	return nil
 }
 
+// getIssueProfile returns profile issue was started with
+func getIssueProfile(config IssuectlConfig, issue *IssueConfig) (*Profile, error) {
+	profile := config.GetProfile(issue.Profile)
+	if profile == nil {
+		return nil, fmt.Errorf("profile %v used by issue %v is not defined", issue.Profile, issue.ID)
+	}
+	return profile, nil
+}
+
+// defaultRepository returns default repository of profile, failing when it's not defined
+func defaultRepository(config IssuectlConfig, profile *Profile) (*RepoConfig, error) {
+	repo := config.GetRepository(profile.DefaultRepository)
+	if repo == nil {
+		return nil, fmt.Errorf("default repository %v of profile %v is not defined", profile.DefaultRepository, profile.Name)
+	}
+	return repo, nil
+}
+
 // isIssueIdInUse checks if issue ID is already in use
 func isIssueIdInUse(config IssuectlConfig, issueID IssueID) bool {
 	_, found := config.GetIssue(issueID)
 	return found
 }
 
-// getIssueAndBranchName gets issue and prepares branch name
-func getBranchName(config IssuectlConfig, issueBackend IssueBackend, profile *Profile, issueID IssueID) (string, error) {
-	repo := config.GetRepository(profile.DefaultRepository)
-	issue, err := issueBackend.GetIssue(repo.Owner, repo.Name, issueID)
+// getBranchName renders branch name of issue from profile BranchTemplate
+func getBranchName(profile *Profile, issueID IssueID, title string) (string, error) {
+	branchTemplate := profile.BranchTemplate
+	if branchTemplate == "" {
+		branchTemplate = defaultBranchTemplate
+	}
+	branchName, err := renderTemplate("branchTemplate", branchTemplate, struct {
+		ID    IssueID
+		Title string
+	}{issueID, title})
 	if err != nil {
-		return "", fmt.Errorf(errFailedToGetIssue, err)
+		return "", err
 	}
+	return sanitizeBranchName(branchName), nil
+}
 
+// sanitizeBranchName replaces characters which don't belong in branch names
+func sanitizeBranchName(branchName string) string {
 	toReplace := []string{
 		" ",
 		",",
@@ -123,30 +238,15 @@ func getBranchName(config IssuectlConfig, issueBackend IssueBackend, profile *Pr
 		"&",
 		"*",
 	}
-
-	switch t := issue.(type) {
-	default:
-		return "", fmt.Errorf("Missing issue type")
-	case *github.Issue:
-		Log.V(5).Infof("%v", t)
-		branchName := fmt.Sprintf("%v-%v", issueID, *issue.(*github.Issue).Title)
-		for _, charToReplace := range toReplace {
-			branchName = strings.ReplaceAll(branchName, charToReplace, "-")
-		}
-		return branchName, nil
-	case *jira.Issue:
-		Log.V(5).Infof("%v", t)
-		branchName := fmt.Sprintf("%v-%v", issueID, issue.(*jira.Issue).Fields.Summary)
-		for _, charToReplace := range toReplace {
-			branchName = strings.ReplaceAll(branchName, charToReplace, "-")
-		}
-		return branchName, nil
+	for _, charToReplace := range toReplace {
+		branchName = strings.ReplaceAll(branchName, charToReplace, "-")
 	}
+	return branchName
 }
 
 // createAndAddRepositoriesToIssue prepares issue and clones repositories to it
 func createAndAddRepositoriesToIssue(
-	config IssuectlConfig, profile *Profile, issueID IssueID, issueDirPath string, branchName, issueTitle string, repositories []string) (*IssueConfig, error) {
+	ctx context.Context, config IssuectlConfig, profile *Profile, issueID IssueID, issueDirPath string, branchName, issueTitle string, repositories []string) (*IssueConfig, error) {
 	newIssue := &IssueConfig{
 		Name:         issueTitle,
 		ID:           issueID,
@@ -158,7 +258,7 @@ func createAndAddRepositoriesToIssue(
 	}
 
 	for _, repoName := range repositories {
-		err := cloneAndAddRepositoryToIssue(config, profile, newIssue, issueDirPath, branchName, repoName)
+		err := cloneAndAddRepositoryToIssue(ctx, config, profile, newIssue, issueDirPath, branchName, repoName)
 		if err != nil {
 			return nil, err
 		}
@@ -167,23 +267,46 @@ func createAndAddRepositoriesToIssue(
 	return newIssue, nil
 }
 
+// cloneBaseBranch returns branch issue branch starts at in freshly cloned repository in dir. Like in other commands,
+// baseBranch of profile is overridden by .issuectl.yaml of the clone and then of current directory.
+// Empty base branch means HEAD of the clone.
+func cloneBaseBranch(profile *Profile, dir string) (string, error) {
+	effective, err := EffectiveProfile(profile, dir, currentProjectDir())
+	if err != nil {
+		return "", err
+	}
+	return effective.BaseBranch, nil
+}
+
 // cloneAndAddRepositoryToIssue clones repository and adds it to issue
-func cloneAndAddRepositoryToIssue(config IssuectlConfig, profile *Profile, issue *IssueConfig, issueDirPath string, branchName string, repoName string) error {
+func cloneAndAddRepositoryToIssue(ctx context.Context, config IssuectlConfig, profile *Profile, issue *IssueConfig, issueDirPath string, branchName string, repoName string) error {
 	gitUser, _ := config.GetGitUser(profile.GitUserName)
 	repo := config.GetRepository(RepoConfigName(repoName))
 	if repo == nil {
 		return fmt.Errorf("Repo %v not defined.", repoName)
 	}
+	git, err := profileGitClient(profile)
+	if err != nil {
+		return err
+	}
 
 	Log.V(3).Infof("Cloning repo %v", repo.Name)
 
-	repoDirPath, err := cloneRepo(repo, issueDirPath, gitUser)
+	repoDirPath, err := cloneRepo(ctx, git, repo, issueDirPath, gitUser)
 	if err != nil {
 		return err
 	}
 
+	base, err := cloneBaseBranch(profile, repoDirPath)
+	if err != nil {
+		return err
+	}
 	Log.V(2).Infof("Creating branch")
-	if err := createBranch(repoDirPath, branchName, gitUser); err != nil {
+	if err := createBranch(ctx, git, repoDirPath, branchName, base, gitUser); err != nil {
+		return err
+	}
+
+	if err := installCommitHooks(ctx, git, profile, issue, repoDirPath); err != nil {
 		return err
 	}
 
@@ -191,14 +314,16 @@ func cloneAndAddRepositoryToIssue(config IssuectlConfig, profile *Profile, issue
 	return nil
 }
 
-func AddRepoToIssue(repoName string, issueID IssueID) error {
+func AddRepoToIssue(ctx context.Context, config IssuectlConfig, repoName string, issueID IssueID) error {
 	Log.Infofp("➡️", "Adding repo %v to issue %v", repoName, issueID)
-	config := LoadConfig()
 	issue, found := config.GetIssue(issueID)
 	if !found {
 		return fmt.Errorf("Issue not found")
 	}
-	profile := config.GetProfile(issue.Profile)
+	profile, err := getIssueProfile(config, issue)
+	if err != nil {
+		return err
+	}
 	gitUser, found := config.GetGitUser(profile.GitUserName)
 	if !found {
 		return fmt.Errorf("Git user not found")
@@ -207,43 +332,95 @@ func AddRepoToIssue(repoName string, issueID IssueID) error {
 	if repo == nil {
 		return fmt.Errorf("Repo %v not defined", repoName)
 	}
+	git, err := profileGitClient(profile)
+	if err != nil {
+		return err
+	}
+
+	if err := runIssueHooks(ctx, config, HookPreAddRepo, profile, issue, []RepoConfigName{repo.Name}); err != nil {
+		return err
+	}
 
 	issue.Repositories = append(issue.Repositories, repo.Name)
 
 	Log.Infofp("🛬", "Cloning repository")
-	repoDirPath, err := cloneRepo(repo, issue.Dir, gitUser)
+	repoDirPath, err := cloneRepo(ctx, git, repo, issue.Dir, gitUser)
 	if err != nil {
 		return err
 	}
 
+	base, err := cloneBaseBranch(profile, repoDirPath)
+	if err != nil {
+		return err
+	}
 	Log.Infofp("🎋", "Setting up branch")
-	if err := createBranch(repoDirPath, issue.BranchName, gitUser); err != nil {
+	if err := createBranch(ctx, git, repoDirPath, issue.BranchName, base, gitUser); err != nil {
+		return err
+	}
+	if err := installCommitHooks(ctx, git, profile, issue, repoDirPath); err != nil {
+		return err
+	}
+	// repository is recorded before post hooks, so that failing hook doesn't leave clone unknown to issue
+	if err := config.AddIssue(issue); err != nil {
+		return err
+	}
+
+	if err := runIssueHooks(ctx, config, HookPostAddRepo, profile, issue, []RepoConfigName{repo.Name}); err != nil {
 		return err
 	}
 	Log.Infofp("🚀", "Done!")
-	return config.AddIssue(issue)
+	return nil
 }
 
 // OpenPullRequest opens pull request
-func OpenPullRequest(issueID IssueID, customTitle string) error {
-	config := LoadConfig()
-
+func OpenPullRequest(ctx context.Context, config IssuectlConfig, issueID IssueID, customTitle string) error {
 	issue, found := config.GetIssue(issueID)
 	if !found {
 		return errors.New("Issue not found")
 	}
-	profile := config.GetProfile(issue.Profile)
+	profile, err := getIssueProfile(config, issue)
+	if err != nil {
+		return err
+	}
+	if err := ValidateProfile(config, profile); err != nil {
+		return err
+	}
 
-	if profile.RepoBackend == "" {
-		return errors.New("Repository Backend not defined")
+	support, err := CheckOperationSupport(config, profile, OperationOpenPR)
+	if err != nil {
+		return err
 	}
 
-	repoBackend, err := getRepoBackendConfigurator(config.GetBackend(profile.RepoBackend))
+	repoBackend, err := getRepoBackendConfigurator(ctx, config.GetBackend(profile.RepoBackend))
 	if err != nil {
 		return err
 	}
 
-	repo := config.GetRepository(profile.DefaultRepository)
+	repo, err := defaultRepository(config, profile)
+	if err != nil {
+		return err
+	}
+
+	profile, err = EffectiveProfile(profile, filepath.Join(issue.Dir, string(repo.Name)), currentProjectDir())
+	if err != nil {
+		return err
+	}
+	baseBranch := profile.BaseBranch
+	if baseBranch == "" {
+		baseBranch = defaultBaseBranch
+	}
+	prTemplate := profile.PRTemplate
+	if prTemplate == "" {
+		prTemplate = defaultPRTemplate
+	}
+	body, err := renderTemplate("prTemplate", prTemplate, struct {
+		ID     IssueID
+		Title  string
+		Branch string
+	}{issue.ID, issue.Name, issue.BranchName})
+	if err != nil {
+		return err
+	}
 
 	titleText := customTitle
 	if titleText == "" {
@@ -252,6 +429,10 @@ func OpenPullRequest(issueID IssueID, customTitle string) error {
 
 	title := fmt.Sprintf("%v | %v", issue.ID, titleText)
 
+	if err := runIssueHooks(ctx, config, HookPreOpenPR, profile, issue, []RepoConfigName{repo.Name}); err != nil {
+		return err
+	}
+
 	Log.Infofp("📂", "Opening PR for issue %v in %v/%v [%v]",
 		issueID,
 		repo.Owner,
@@ -260,40 +441,74 @@ func OpenPullRequest(issueID IssueID, customTitle string) error {
 	)
 
 	prId, err := repoBackend.OpenPullRequest(
+		ctx,
 		repo.Owner,
 		repo.Name,
 		title,
-		fmt.Sprintf("Resolves #%v ✅", issue.ID),
-		"master", // TODO: make configurable
+		body,
+		baseBranch,
 		issue.BranchName,
 	)
 	if err != nil {
 		return err
 	}
 
-	issueBackend, err := getIssueBackendConfigurator(config.GetBackend(profile.IssueBackend))
+	if support.Has(CapabilityLinkPullRequests) {
+		if err := linkPullRequest(ctx, config, profile, repo, issueID, *prId); err != nil {
+			return err
+		}
+	} else if profile.IssueBackend != "" {
+		Log.Infofp("🤷", "Issue backend %v doesn't support linking PRs, skipping", profile.IssueBackend)
+	}
+
+	return runIssueHooks(ctx, config, HookPostOpenPR, profile, issue, []RepoConfigName{repo.Name})
+}
+
+// linkPullRequest links pull request to issue in issue backend of profile
+func linkPullRequest(ctx context.Context, config IssuectlConfig, profile *Profile, repo *RepoConfig, issueID IssueID, prID int) error {
+	issueBackend, err := getIssueBackendConfigurator(ctx, config.GetBackend(profile.IssueBackend))
 	if err != nil {
 		return err
 	}
-	Log.Infofp("🔗", "Linking PR %v to issue %v in %v", *prId, issueID, profile.IssueBackend)
-	time.Sleep(time.Second * 2) // FIXME: PR might not yet be available in API
-	return issueBackend.LinkIssueToRepo(repo.Owner, repo.Name, issueID, strconv.Itoa(*prId))
+	Log.Infofp("🔗", "Linking PR %v to issue %v in %v", prID, issueID, profile.IssueBackend)
+	// FIXME: PR might not yet be available in API
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(pullRequestLinkDelay):
+	}
+	return issueBackend.LinkIssueToRepo(ctx, repo.Owner, repo.Name, issueID, strconv.Itoa(prID))
 }
 
 // FinishWorkingOnIssue finishes work on an issue
-func FinishWorkingOnIssue(issueID IssueID) error {
-	config := LoadConfig().GetPersistent()
+func FinishWorkingOnIssue(ctx context.Context, config IssuectlConfig, issueID IssueID) error {
 	issue, found := config.GetIssue(issueID)
 	if !found {
 		return errors.New("Issue not found")
 	}
-	profile := config.GetProfile(issue.Profile)
+	profile, err := getIssueProfile(config, issue)
+	if err != nil {
+		return err
+	}
+	if err := ValidateProfile(config, profile); err != nil {
+		return err
+	}
 
-	repo := config.GetRepository(profile.DefaultRepository)
+	support, err := CheckOperationSupport(config, profile, OperationFinish)
+	if err != nil {
+		return err
+	}
 
 	Log.Infofp("🥂", "Finishing work on %v", issueID)
-	if profile.IssueBackend != "" {
-		issueBackend, err := getIssueBackendConfigurator(config.GetBackend(profile.IssueBackend))
+	if err := runIssueHooks(ctx, config, HookPreFinish, profile, issue, issue.Repositories); err != nil {
+		return err
+	}
+	if support.Has(CapabilityIssueTracking) {
+		repo, err := defaultRepository(config, profile)
+		if err != nil {
+			return err
+		}
+		issueBackend, err := getIssueBackendConfigurator(ctx, config.GetBackend(profile.IssueBackend))
 		if err != nil {
 			return err
 		}
@@ -301,6 +516,7 @@ func FinishWorkingOnIssue(issueID IssueID) error {
 		Log.Infofp("🏁", "Closing issue %v in %v", issueID, profile.IssueBackend)
 
 		err = issueBackend.CloseIssue(
+			ctx,
 			repo.Owner,
 			repo.Name,
 			issueID,
@@ -323,6 +539,15 @@ func FinishWorkingOnIssue(issueID IssueID) error {
 		return err
 	}
 
+	if err := runIssueHooks(ctx, config, HookPostFinish, profile, issue, issue.Repositories); err != nil {
+		return err
+	}
+
+	// killed last, finish can be running inside of the session
+	if err := KillIssueSession(ctx, config, issue); err != nil {
+		return err
+	}
+
 	Log.Infofp("👍", "All done!")
 
 	return nil
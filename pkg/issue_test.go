@@ -0,0 +1,133 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// runLifecycle runs start -> openpr -> finish for issueID and checks local side effects
+func runLifecycle(t *testing.T, env *testEnv, issueID IssueID, expectedBranch string) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := StartWorkingOnIssue(ctx, "", env.config, issueID); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+
+	issue, found := env.config.GetIssue(issueID)
+	if !found {
+		t.Fatalf("issue %v not added to config", issueID)
+	}
+	if issue.BranchName != expectedBranch {
+		t.Errorf("expected branch %v, got %v", expectedBranch, issue.BranchName)
+	}
+	for repoName, repo := range env.repos {
+		if !repo.hasBranch(t, expectedBranch) {
+			t.Errorf("branch %v not pushed to %v", expectedBranch, repoName)
+		}
+	}
+
+	if err := OpenPullRequest(ctx, env.config, issueID, ""); err != nil {
+		t.Fatalf("OpenPullRequest() failed: %s", err)
+	}
+
+	if err := FinishWorkingOnIssue(ctx, env.config, issueID); err != nil {
+		t.Fatalf("FinishWorkingOnIssue() failed: %s", err)
+	}
+	if _, found := env.config.GetIssue(issueID); found {
+		t.Errorf("issue %v still in config after finish", issueID)
+	}
+	if _, err := os.Stat(issue.Dir); !os.IsNotExist(err) {
+		t.Errorf("issue dir %v not removed after finish", issue.Dir)
+	}
+}
+
+// TestLifecycleGitHub tests full issue lifecycle with GitHub as issue and repo backend.
+func TestLifecycleGitHub(t *testing.T) {
+	server := newAPIServer(t, "github/lifecycle")
+	backend := githubFixtureBackend(server)
+	env := newTestEnv(t, backend, backend, "service")
+
+	runLifecycle(t, env, "42", "42-Fix-login-bug")
+}
+
+// TestLifecycleJira tests full issue lifecycle with Jira as issue backend and GitHub as repo backend.
+func TestLifecycleJira(t *testing.T) {
+	jira := newAPIServer(t, "jira/lifecycle")
+	github := newAPIServer(t, "github/pull_request")
+	env := newTestEnv(t, jiraFixtureBackend(jira), githubFixtureBackend(github), "service")
+
+	runLifecycle(t, env, "PROJ-1", "PROJ-1-Add-feature")
+}
+
+// TestLifecycleGitLab tests full issue lifecycle with GitLab as issue and repo backend.
+func TestLifecycleGitLab(t *testing.T) {
+	server := newAPIServer(t, "gitlab/lifecycle")
+	backend := gitlabFixtureBackend(server)
+	env := newTestEnv(t, backend, backend, "service")
+
+	runLifecycle(t, env, "5", "5")
+}
+
+// TestStartRollback tests that failed start doesn't leave issue workdir behind.
+func TestStartRollback(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	if err := env.config.AddRepository(&RepoConfig{Name: "broken", RepoURL: "/nonexistent/repo.git"}); err != nil {
+		t.Fatalf("AddRepository() failed: %s", err)
+	}
+	env.updateProfile(t, func(profile *Profile) { profile.Repositories = append(profile.Repositories, "broken") })
+
+	if err := StartWorkingOnIssue(context.Background(), "", env.config, "13"); err == nil {
+		t.Fatalf("expected error when cloning broken repository")
+	}
+	entries, err := os.ReadDir(env.workDir)
+	if err != nil {
+		t.Fatalf("failed to read workdir: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected empty workdir after failed start, got %v entries", len(entries))
+	}
+}
+
+// TestMissingDefaultRepository tests that openpr and finish fail instead of panicking when default repository
+// of issue profile was removed from hand edited config.
+func TestMissingDefaultRepository(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service", "web")
+	env.updateProfile(t, func(profile *Profile) { profile.Repositories = []RepoConfigName{"web"} })
+	ctx := context.Background()
+	if err := StartWorkingOnIssue(ctx, "", env.config, "3"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	delete(env.config.(*issuectlConfig).Repositories, "service")
+
+	if err := OpenPullRequest(ctx, env.config, "3", ""); err == nil || !strings.Contains(err.Error(), "default repository service") {
+		t.Errorf("expected openpr to fail on missing default repository, got %v", err)
+	}
+	if err := FinishWorkingOnIssue(ctx, env.config, "3"); err == nil || !strings.Contains(err.Error(), "default repository service") {
+		t.Errorf("expected finish to fail on missing default repository, got %v", err)
+	}
+}
+
+// TestGitHubIssueURL tests that issue URLs point to configured enterprise host.
+func TestGitHubIssueURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "https://github.com/org/repo/issues/13",
+		"https://github.example.com":         "https://github.example.com/org/repo/issues/13",
+		"https://github.example.com/api/v3/": "https://github.example.com/org/repo/issues/13",
+	}
+	for host, expected := range tests {
+		client, err := NewGitHubClient("token", host, "tester")
+		if err != nil {
+			t.Fatalf("NewGitHubClient(%q) failed: %v", host, err)
+		}
+		url, err := client.GetIssueURL("org", "repo", "13")
+		if err != nil {
+			t.Fatalf("GetIssueURL() failed: %v", err)
+		}
+		if url != expected {
+			t.Errorf("GetIssueURL() with host %q = %v, want %v", host, url, expected)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package issuectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// defaultLauncherCommand is used when launcher isn't configured and neither $VISUAL nor $EDITOR is set
+const defaultLauncherCommand = "code"
+
+// defaultLauncherArgs opens issue directory, or workspace file when it's generated
+var defaultLauncherArgs = []string{"{{if .Workspace}}{{.Workspace}}{{else}}{{.Dir}}{{end}}"}
+
+// LauncherConfig describes how issue is opened in editor or IDE by workon
+type LauncherConfig struct {
+	// Command is executable of editor, e.g. code, cursor, goland or nvim
+	Command string `yaml:"command,omitempty"`
+	// Args are Go templates with .ID, .Name, .Dir, .Workspace, .Branch and .Repositories of issue
+	// [defaults to workspace file when generated, issue directory otherwise]
+	Args []string `yaml:"args,omitempty"`
+	// Workspace generates multi-root .code-workspace file with all issue repositories before launching
+	Workspace bool `yaml:"workspace,omitempty"`
+}
+
+// launchData is passed to templates of launcher arguments
+type launchData struct {
+	ID           IssueID
+	Name         string
+	Dir          string
+	Workspace    string
+	Branch       string
+	Repositories []string
+}
+
+// validateLauncher checks that launcher arguments are valid templates
+func validateLauncher(launcher *LauncherConfig) error {
+	if launcher == nil {
+		return nil
+	}
+	for _, arg := range launcher.Args {
+		if _, err := template.New("launcher argument").Parse(arg); err != nil {
+			return fmt.Errorf("invalid launcher argument %q: %w", arg, err)
+		}
+	}
+	return nil
+}
+
+// IssueLauncher returns launcher of issue, set on issue, its profile or, as fallback, using $VISUAL or $EDITOR
+func IssueLauncher(config IssuectlConfig, issue *IssueConfig) *LauncherConfig {
+	if issue.Launcher != nil && issue.Launcher.Command != "" {
+		return issue.Launcher
+	}
+	if profile := config.GetProfile(issue.Profile); profile != nil && profile.Launcher != nil && profile.Launcher.Command != "" {
+		return profile.Launcher
+	}
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return &LauncherConfig{Command: editor}
+		}
+	}
+	return &LauncherConfig{Command: defaultLauncherCommand}
+}
+
+// LaunchIssue opens issue with launcher, waiting until launched command exits
+func LaunchIssue(ctx context.Context, config IssuectlConfig, issueID IssueID, launcher *LauncherConfig) error {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return fmt.Errorf("issue %v not found", issueID)
+	}
+	if launcher == nil {
+		launcher = IssueLauncher(config, issue)
+	}
+
+	data := launchData{ID: issue.ID, Name: issue.Name, Dir: issue.Dir, Branch: issue.BranchName}
+	for _, repo := range issue.Repositories {
+		data.Repositories = append(data.Repositories, string(repo))
+	}
+	if launcher.Workspace {
+		workspace, err := WriteCodeWorkspace(issue)
+		if err != nil {
+			return err
+		}
+		data.Workspace = workspace
+	}
+
+	// $EDITOR and command can contain arguments, e.g. "nvim -p"
+	command := strings.Fields(launcher.Command)
+	if len(command) == 0 {
+		return fmt.Errorf("launcher command is empty")
+	}
+	args := launcher.Args
+	if len(args) == 0 {
+		args = defaultLauncherArgs
+	}
+	for _, arg := range args {
+		rendered, err := renderTemplate("launcher argument", arg, data)
+		if err != nil {
+			return err
+		}
+		command = append(command, rendered)
+	}
+
+	Log.Infofp("🚪", "Opening issue %v with %v", issue.ID, command[0])
+	Log.V(3).Infof("%v", strings.Join(command, " "))
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = issue.Dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to run %v: %w", command[0], err)
+	}
+	return nil
+}
+
+type codeWorkspaceFolder struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path"`
+}
+
+// WriteCodeWorkspace writes multi-root workspace file, readable by VS Code and Cursor, listing every issue repository.
+// Other content of existing workspace file, like settings, is kept.
+func WriteCodeWorkspace(issue *IssueConfig) (string, error) {
+	path := filepath.Join(issue.Dir, issue.Name+".code-workspace")
+	workspace := map[string]interface{}{"settings": map[string]interface{}{}}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &workspace); err != nil {
+			return "", fmt.Errorf("failed to read workspace file %v: %w", path, err)
+		}
+	}
+
+	folders := []codeWorkspaceFolder{}
+	for _, repo := range issue.Repositories {
+		folders = append(folders, codeWorkspaceFolder{Path: string(repo)})
+	}
+	if len(folders) == 0 {
+		folders = append(folders, codeWorkspaceFolder{Name: issue.Name, Path: "."})
+	}
+	workspace["folders"] = folders
+
+	data, err := json.MarshalIndent(workspace, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	Log.V(3).Infof("Workspace file written to %v", path)
+	return path, nil
+}
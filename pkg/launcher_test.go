@@ -0,0 +1,90 @@
+package issuectl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestIssueLauncher tests that launcher of issue wins over profile, $VISUAL and $EDITOR.
+func TestIssueLauncher(t *testing.T) {
+	config := GetConfig("work", nil, nil, nil, map[ProfileName]*Profile{
+		"work": {Name: "work", Launcher: &LauncherConfig{Command: "goland"}},
+		"priv": {Name: "priv"},
+	}).GetInMemory()
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	tests := []struct {
+		issue    *IssueConfig
+		visual   string
+		editor   string
+		expected string
+	}{
+		{&IssueConfig{Profile: "work", Launcher: &LauncherConfig{Command: "cursor"}}, "", "", "cursor"},
+		{&IssueConfig{Profile: "work"}, "vim", "", "goland"},
+		{&IssueConfig{Profile: "priv"}, "vim", "nano", "vim"},
+		{&IssueConfig{Profile: "priv"}, "", "nano", "nano"},
+		{&IssueConfig{Profile: "priv"}, "", "", defaultLauncherCommand},
+	}
+	for _, test := range tests {
+		t.Setenv("VISUAL", test.visual)
+		t.Setenv("EDITOR", test.editor)
+		if command := IssueLauncher(config, test.issue).Command; command != test.expected {
+			t.Errorf("expected launcher %v, got %v", test.expected, command)
+		}
+	}
+}
+
+// TestLaunchIssue tests rendering launcher arguments and generating workspace file.
+func TestLaunchIssue(t *testing.T) {
+	dir := t.TempDir()
+	issue := &IssueConfig{ID: "XY-69", Name: "XY-69-login", Dir: dir, Repositories: []RepoConfigName{"api", "web"}}
+	config := GetConfig("", nil, nil, nil, nil).GetInMemory()
+	if err := config.AddIssue(issue); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+	existing := `{"folders": [], "settings": {"editor.tabSize": 2}}`
+	workspacePath := filepath.Join(dir, "XY-69-login.code-workspace")
+	if err := os.WriteFile(workspacePath, []byte(existing), 0644); err != nil {
+		t.Fatalf("failed to write workspace: %s", err)
+	}
+
+	launcher := &LauncherConfig{
+		Command:   "sh -c",
+		Args:      []string{`echo "$0 $1" > launched`, "{{.ID}}", "{{.Workspace}}"},
+		Workspace: true,
+	}
+	if err := LaunchIssue(context.Background(), config, "XY-69", launcher); err != nil {
+		t.Fatalf("LaunchIssue() failed: %s", err)
+	}
+
+	launched, err := os.ReadFile(filepath.Join(dir, "launched"))
+	if err != nil {
+		t.Fatalf("launcher didn't run in issue directory: %s", err)
+	}
+	if got := strings.TrimSpace(string(launched)); got != "XY-69 "+workspacePath {
+		t.Errorf("unexpected launcher arguments %q", got)
+	}
+
+	data, err := os.ReadFile(workspacePath)
+	if err != nil {
+		t.Fatalf("failed to read workspace: %s", err)
+	}
+	workspace := struct {
+		Folders  []codeWorkspaceFolder
+		Settings map[string]interface{}
+	}{}
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		t.Fatalf("invalid workspace file: %s", err)
+	}
+	if len(workspace.Folders) != 2 || workspace.Folders[1].Path != "web" {
+		t.Errorf("expected folders of issue repositories, got %+v", workspace.Folders)
+	}
+	if workspace.Settings["editor.tabSize"] != float64(2) {
+		t.Errorf("existing workspace settings not kept, got %v", workspace.Settings)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package issuectl
+
+// fileLock is a no-op lock on platforms without flock
+type fileLock struct{}
+
+// lockFile doesn't lock anything on this platform
+func lockFile(_ string) (*fileLock, error) {
+	return &fileLock{}, nil
+}
+
+// Unlock releases the lock
+func (l *fileLock) Unlock() {}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package issuectl
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileLock is an advisory lock held on a file
+type fileLock struct {
+	file *os.File
+}
+
+// lockFile takes exclusive advisory lock on path, blocking until it's available
+func lockFile(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &fileLock{file: file}, nil
+}
+
+// Unlock releases the lock
+func (l *fileLock) Unlock() {
+	_ = syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	l.file.Close()
+}
@@ -2,18 +2,20 @@ package issuectl
 
 import (
 	"fmt"
-	"log"
+	"io"
 	"os"
 )
 
-// Logger struct holds the verbosity level
+// Logger struct holds the verbosity level and writer logs are written to
 type Logger struct {
 	verbosity int
+	out       io.Writer
 }
 
-// NewLogger parses command line argument for verbosity level and returns a new Logger
+// NewLogger parses command line argument for verbosity level and returns a new Logger.
+// Logs go to stderr so that stdout only contains command output, e.g. with --output json.
 func NewLogger() *Logger {
-	return &Logger{verbosity: 1} // FIXME: get verbosity level from cobra
+	return &Logger{verbosity: 1, out: os.Stderr} // FIXME: get verbosity level from cobra
 }
 
 // Infof formats the log message and calls output function with default level 1
@@ -45,12 +47,7 @@ func (v *verbosityLogger) Infof(format string, args ...interface{}) {
 // output logs the message if the logger's verbosity level is greater than or equal to the level
 func (l *Logger) output(level int, message string) {
 	if l.verbosity >= level {
-		// _, file, _, _ := runtime.Caller(2)
-		// file = file[strings.LastIndex(file, "/")+1:]
-		log.SetOutput(os.Stdout)
-		log.SetFlags(0)
-		// log.Printf("❖ %v|%s| %s\n", time.Now().Format(time.RFC3339), file, message)
-		log.Printf("%s\n", message)
+		fmt.Fprintln(l.out, message)
 	}
 }
 
@@ -0,0 +1,143 @@
+package issuectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// OutputFormat is a format commands print their results in
+type OutputFormat string
+
+const (
+	OutputTable    OutputFormat = "table"
+	OutputJSON     OutputFormat = "json"
+	OutputYAML     OutputFormat = "yaml"
+	OutputTemplate OutputFormat = "template"
+)
+
+// RedactedValue replaces secrets in printed output
+const RedactedValue = "<redacted>"
+
+// ParseOutputFormat checks that format is one of supported output formats
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch OutputFormat(format) {
+	case OutputTable, OutputJSON, OutputYAML, OutputTemplate:
+		return OutputFormat(format), nil
+	}
+	return "", fmt.Errorf("unsupported output format %q, use one of table, json, yaml or template", format)
+}
+
+// WriteOutput writes value as JSON, YAML or result of Go template text.
+// Field names are the same in all formats and match names used in config file,
+// so JSON documents and templates follow config file schema, e.g. {{range .}}{{.name}}{{end}}.
+func WriteOutput(w io.Writer, format OutputFormat, text string, value interface{}) error {
+	switch format {
+	case OutputYAML:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case OutputJSON, OutputTemplate:
+		plain, err := toPlainValue(value)
+		if err != nil {
+			return err
+		}
+		if format == OutputTemplate {
+			tmpl, err := template.New("output").Option("missingkey=error").Parse(text)
+			if err != nil {
+				return fmt.Errorf("invalid output template: %w", err)
+			}
+			return tmpl.Execute(w, plain)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(plain)
+	}
+	return fmt.Errorf("output format %v can't be written as document", format)
+}
+
+// toPlainValue turns value into maps, slices and scalars keyed by yaml names of fields
+func toPlainValue(value interface{}) (interface{}, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return stringKeys(decoded), nil
+}
+
+// stringKeys converts maps decoded from YAML to maps with string keys, which can be encoded as JSON
+func stringKeys(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, item := range typed {
+			converted[i] = stringKeys(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// RedactSecrets returns copy of backend with tokens stored in config replaced by RedactedValue
+func RedactSecrets(backend *BackendConfig) *BackendConfig {
+	redact := func(token *string) {
+		if *token != "" {
+			*token = RedactedValue
+		}
+	}
+	copied := *backend
+	copied.GitHub, copied.GitLab, copied.Jira = copyBackendSettings(backend)
+	if copied.GitHub != nil {
+		redact(&copied.GitHub.Token)
+	}
+	if copied.GitLab != nil {
+		redact(&copied.GitLab.Token)
+	}
+	if copied.Jira != nil {
+		redact(&copied.Jira.Token)
+	}
+	return &copied
+}
+
+// RedactedConfig returns in memory copy of config with secrets of all backends redacted
+func RedactedConfig(config IssuectlConfig) (IssuectlConfig, error) {
+	ic, ok := config.(*issuectlConfig)
+	if !ok {
+		return nil, fmt.Errorf("unsupported config implementation %T", config)
+	}
+	cloned, err := ic.clone()
+	if err != nil {
+		return nil, err
+	}
+	for name, backend := range cloned.Backends {
+		cloned.Backends[name] = RedactSecrets(backend)
+	}
+	cloned._path = ic._path
+	return cloned.GetInMemory(), nil
+}
+
+// SortedValues returns values of map ordered by their keys
+func SortedValues[K ~string, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, key := range SortedKeys(m) {
+		values = append(values, m[key])
+	}
+	return values
+}
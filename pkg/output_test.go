@@ -0,0 +1,81 @@
+package issuectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestWriteOutput tests that every format uses field names from config file.
+func TestWriteOutput(t *testing.T) {
+	repos := SortedValues(map[RepoConfigName]*RepoConfig{
+		"web": {Name: "web", Owner: "org", RepoURL: "git@example.com:org/web.git"},
+		"api": {Name: "api", Owner: "org", RepoURL: "git@example.com:org/api.git"},
+	})
+
+	var out bytes.Buffer
+	if err := WriteOutput(&out, OutputJSON, "", repos); err != nil {
+		t.Fatalf("WriteOutput() failed: %s", err)
+	}
+	decoded := []map[string]string{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON output %q: %s", out.String(), err)
+	}
+	if len(decoded) != 2 || decoded[0]["name"] != "api" || decoded[1]["url"] != "git@example.com:org/web.git" {
+		t.Errorf("unexpected JSON output %v", decoded)
+	}
+
+	out.Reset()
+	if err := WriteOutput(&out, OutputTemplate, `{{range .}}{{.name}}={{.owner}};{{end}}`, repos); err != nil {
+		t.Fatalf("WriteOutput() failed: %s", err)
+	}
+	if out.String() != "api=org;web=org;" {
+		t.Errorf("unexpected template output %q", out.String())
+	}
+
+	out.Reset()
+	if err := WriteOutput(&out, OutputYAML, "", repos); err != nil {
+		t.Fatalf("WriteOutput() failed: %s", err)
+	}
+	if !strings.HasPrefix(out.String(), "- name: api\n") {
+		t.Errorf("unexpected YAML output %q", out.String())
+	}
+
+	if err := WriteOutput(&out, OutputTemplate, `{{.missing}}`, map[string]string{}); err == nil {
+		t.Errorf("expected error for missing template key")
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
+
+// TestRedactSecrets tests that tokens are redacted in copies and kept in config.
+func TestRedactSecrets(t *testing.T) {
+	config := GetConfig("", nil, map[BackendConfigName]*BackendConfig{
+		"gh":   {Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{Token: EncodeToken("secret")}},
+		"jira": {Name: "jira", Type: BackendJira, Jira: &JiraConfig{Host: "https://jira.example.com"}},
+	}, nil, nil).GetInMemory()
+
+	redacted, err := RedactedConfig(config)
+	if err != nil {
+		t.Fatalf("RedactedConfig() failed: %s", err)
+	}
+	if token := redacted.GetBackend("gh").GitHub.Token; token != RedactedValue {
+		t.Errorf("expected token to be redacted, got %q", token)
+	}
+	if token := redacted.GetBackend("jira").Jira.Token; token != "" {
+		t.Errorf("expected empty token to stay empty, got %q", token)
+	}
+	if token := config.GetBackend("gh").GitHub.Token; token != EncodeToken("secret") {
+		t.Errorf("redaction modified config, token %q", token)
+	}
+
+	var out bytes.Buffer
+	if err := WriteOutput(&out, OutputJSON, "", redacted); err != nil {
+		t.Fatalf("WriteOutput() failed: %s", err)
+	}
+	if strings.Contains(out.String(), EncodeToken("secret")) {
+		t.Errorf("token leaked to output %v", out.String())
+	}
+}
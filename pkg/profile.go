@@ -1,11 +1,29 @@
 package issuectl
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 // ProfileName is a name of issuectl config profile
 type ProfileName string
 
+const (
+	// MergeListsAppend appends lists of profile to ones inherited from parent
+	MergeListsAppend = "append"
+	// MergeListsReplace replaces lists inherited from parent
+	MergeListsReplace = "replace"
+)
+
 // Profile is a config profile
 type Profile struct {
-	Name         ProfileName       `yaml:"name"`
+	Name ProfileName `yaml:"name"`
+	// Extends is a name of profile this one inherits settings from
+	Extends ProfileName `yaml:"extends,omitempty"`
+	// MergeLists tells if lists, like repositories, are appended to inherited ones or replace them [defaults to append]
+	MergeLists string `yaml:"mergeLists,omitempty"`
+
 	WorkDir      string            `yaml:"workDir"`
 	IssueBackend BackendConfigName `yaml:"issueBackend"`
 	RepoBackend  BackendConfigName `yaml:"repoBackend"`
@@ -14,9 +32,220 @@ type Profile struct {
 
 	// DefaultRepository is now used for Github IssueBackend
 	DefaultRepository RepoConfigName `yaml:"defaultRepository"`
+
+	// BaseBranch is branch pull requests are opened against [defaults to master]
+	BaseBranch string `yaml:"baseBranch,omitempty"`
+	// BranchTemplate is Go template of branch name, with .ID and .Title of issue [defaults to {{.ID}}-{{.Title}}]
+	BranchTemplate string `yaml:"branchTemplate,omitempty"`
+	// PRTemplate is Go template of pull request body, with .ID, .Title and .Branch of issue
+	PRTemplate string `yaml:"prTemplate,omitempty"`
+	// CommitTemplate is Go template of commit messages, with .ID, .Title and .Branch of issue and .Message [defaults to {{.ID}}: {{.Message}}]
+	CommitTemplate string `yaml:"commitTemplate,omitempty"`
+	// SyncStrategy is how sync updates issue branch with base branch: rebase or merge [defaults to rebase]
+	SyncStrategy string `yaml:"syncStrategy,omitempty"`
+
+	// Launcher opens issues in editor or IDE [defaults to $VISUAL, $EDITOR or VS Code]
+	Launcher *LauncherConfig `yaml:"launcher,omitempty"`
+	// Session configures tmux or zellij session opened by workon --session
+	Session *SessionConfig `yaml:"session,omitempty"`
+	// Hooks are run at issue lifecycle events in issue directory
+	Hooks Hooks `yaml:"hooks,omitempty"`
+	// GitClient is implementation of git operations, exec runs git binary, go-git works without it [defaults to exec]
+	GitClient string `yaml:"gitClient,omitempty"`
+	// CommitHooks are git hooks adding issue key to commit messages, installed in cloned repositories
+	CommitHooks *CommitHooksConfig `yaml:"commitHooks,omitempty"`
+	// TemplateDir is a directory with files copied to each new issue directory, *.tmpl files are rendered with issue data
+	TemplateDir string `yaml:"templateDir,omitempty"`
 }
 
 func (p *Profile) AddRepository(repo RepoConfigName) error {
 	p.Repositories = append(p.Repositories, repo)
 	return nil
 }
+
+// ProfileOrigins maps yaml names of profile fields to profiles their values come from.
+// Lists can be composed from multiple profiles.
+type ProfileOrigins map[string][]ProfileName
+
+// notInheritedFields are fields describing profile itself rather than its settings
+var notInheritedFields = map[string]bool{
+	"Name":       true,
+	"Extends":    true,
+	"MergeLists": true,
+}
+
+// resolveProfile returns profile with settings inherited through `extends` chain and origins of its values
+func resolveProfile(profiles map[ProfileName]*Profile, name ProfileName) (*Profile, ProfileOrigins, error) {
+	chain, err := profileChain(profiles, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved := &Profile{}
+	origins := ProfileOrigins{}
+	// apply from the root ancestor down to the profile itself
+	for i := len(chain) - 1; i >= 0; i-- {
+		mergeProfile(resolved, chain[i], origins)
+	}
+
+	profile := chain[0]
+	resolved.Name = profile.Name
+	resolved.Extends = profile.Extends
+	resolved.MergeLists = profile.MergeLists
+	return resolved, origins, nil
+}
+
+// profileChain returns profile followed by its ancestors, failing on missing parents and cycles
+func profileChain(profiles map[ProfileName]*Profile, name ProfileName) ([]*Profile, error) {
+	chain := []*Profile{}
+	visited := map[ProfileName]bool{}
+	path := []string{}
+	for current := name; current != ""; {
+		path = append(path, string(current))
+		if visited[current] {
+			return nil, fmt.Errorf("profile inheritance cycle: %v", strings.Join(path, " -> "))
+		}
+		visited[current] = true
+
+		profile := profiles[current]
+		if profile == nil {
+			if current == name {
+				return nil, fmt.Errorf("profile %v not defined", name)
+			}
+			return nil, fmt.Errorf("profile %v extends %v which is not defined", chain[len(chain)-1].Name, current)
+		}
+		chain = append(chain, profile)
+		current = profile.Extends
+	}
+	return chain, nil
+}
+
+// mergeProfile sets non empty fields of profile on resolved and records their origin
+func mergeProfile(resolved, profile *Profile, origins ProfileOrigins) {
+	target := reflect.ValueOf(resolved).Elem()
+	source := reflect.ValueOf(profile).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		field := source.Type().Field(i)
+		value := source.Field(i)
+		if notInheritedFields[field.Name] || value.IsZero() {
+			continue
+		}
+		key := yamlFieldName(field)
+
+		if value.Kind() != reflect.Slice || profile.MergeLists == MergeListsReplace {
+			target.Field(i).Set(copyValue(value))
+			origins[key] = []ProfileName{profile.Name}
+			continue
+		}
+
+		merged := target.Field(i)
+		for j := 0; j < value.Len(); j++ {
+			if !sliceContains(merged, value.Index(j)) {
+				merged = reflect.Append(merged, copyValue(value.Index(j)))
+			}
+		}
+		target.Field(i).Set(merged)
+		origins[key] = append(origins[key], profile.Name)
+	}
+}
+
+// unresolveProfile strips settings profile inherits from its parent so that only overrides are stored.
+// Settings stored profile already sets explicitly are kept even when they equal inherited ones,
+// so that profile doesn't start following its parent when they are changed there.
+func unresolveProfile(profiles map[ProfileName]*Profile, profile *Profile) (*Profile, error) {
+	if profile.Extends == "" {
+		return copyProfile(profile), nil
+	}
+	parent, _, err := resolveProfile(profiles, profile.Extends)
+	if err != nil {
+		return nil, err
+	}
+	stored := profiles[profile.Name]
+	if stored == nil {
+		stored = &Profile{}
+	}
+
+	stripped := &Profile{}
+	target := reflect.ValueOf(stripped).Elem()
+	source := reflect.ValueOf(profile).Elem()
+	inherited := reflect.ValueOf(parent).Elem()
+	explicit := reflect.ValueOf(stored).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		field := source.Type().Field(i)
+		value := source.Field(i)
+		switch {
+		case notInheritedFields[field.Name]:
+			target.Field(i).Set(value)
+		case value.Kind() == reflect.Slice && profile.MergeLists != MergeListsReplace:
+			own := reflect.MakeSlice(value.Type(), 0, value.Len())
+			for j := 0; j < value.Len(); j++ {
+				item := value.Index(j)
+				if !sliceContains(inherited.Field(i), item) || sliceContains(explicit.Field(i), item) {
+					own = reflect.Append(own, copyValue(item))
+				}
+			}
+			if own.Len() > 0 {
+				target.Field(i).Set(own)
+			}
+		case !explicit.Field(i).IsZero() || !reflect.DeepEqual(value.Interface(), inherited.Field(i).Interface()):
+			target.Field(i).Set(copyValue(value))
+		}
+	}
+	return stripped, nil
+}
+
+// copyProfile returns deep copy of profile
+func copyProfile(profile *Profile) *Profile {
+	return copyValue(reflect.ValueOf(profile)).Interface().(*Profile)
+}
+
+// copyValue returns deep copy of value, so that resolved profiles don't share slices, maps and pointers with stored ones
+func copyValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(copyValue(value.Elem()))
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyValue(value.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(copyValue(value.Field(i)))
+			}
+		}
+		return copied
+	}
+	return value
+}
+
+func sliceContains(slice, item reflect.Value) bool {
+	for i := 0; i < slice.Len(); i++ {
+		if reflect.DeepEqual(slice.Index(i).Interface(), item.Interface()) {
+			return true
+		}
+	}
+	return false
+}
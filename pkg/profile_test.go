@@ -0,0 +1,170 @@
+package issuectl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func inheritanceConfig() IssuectlConfig {
+	return GetConfig("child", nil, nil, nil, map[ProfileName]*Profile{
+		"base": {
+			Name:         "base",
+			WorkDir:      "/work",
+			GitUserName:  "tester",
+			IssueBackend: "jira",
+			Repositories: []RepoConfigName{"api", "web"},
+		},
+		"child": {
+			Name:         "child",
+			Extends:      "base",
+			WorkDir:      "/child",
+			Repositories: []RepoConfigName{"web", "docs"},
+		},
+		"replacing": {
+			Name:         "replacing",
+			Extends:      "child",
+			MergeLists:   MergeListsReplace,
+			Repositories: []RepoConfigName{"infra"},
+		},
+	}).GetInMemory()
+}
+
+// TestResolveProfile tests field override and list append and replace semantics.
+func TestResolveProfile(t *testing.T) {
+	config := inheritanceConfig()
+
+	child, origins, err := config.ResolveProfile("child")
+	if err != nil {
+		t.Fatalf("ResolveProfile() failed: %s", err)
+	}
+	if child.WorkDir != "/child" || child.GitUserName != "tester" || child.IssueBackend != "jira" {
+		t.Errorf("unexpected resolved profile %+v", child)
+	}
+	if expected := []RepoConfigName{"api", "web", "docs"}; !reflect.DeepEqual(child.Repositories, expected) {
+		t.Errorf("expected repositories %v, got %v", expected, child.Repositories)
+	}
+	if expected := []ProfileName{"base", "child"}; !reflect.DeepEqual(origins["repositories"], expected) {
+		t.Errorf("expected repositories from %v, got %v", expected, origins["repositories"])
+	}
+	if expected := []ProfileName{"base"}; !reflect.DeepEqual(origins["gituser"], expected) {
+		t.Errorf("expected git user from %v, got %v", expected, origins["gituser"])
+	}
+
+	replacing := config.GetProfile("replacing")
+	if expected := []RepoConfigName{"infra"}; !reflect.DeepEqual(replacing.Repositories, expected) {
+		t.Errorf("expected repositories %v, got %v", expected, replacing.Repositories)
+	}
+	if replacing.WorkDir != "/child" {
+		t.Errorf("expected workDir inherited through two levels, got %v", replacing.WorkDir)
+	}
+}
+
+// TestResolveProfileErrors tests detection of broken inheritance.
+func TestResolveProfileErrors(t *testing.T) {
+	config := GetConfig("a", nil, nil, nil, map[ProfileName]*Profile{
+		"a":       {Name: "a", Extends: "b"},
+		"b":       {Name: "b", Extends: "a"},
+		"orphan":  {Name: "orphan", Extends: "missing"},
+		"selfish": {Name: "selfish", Extends: "selfish"},
+	})
+
+	tests := map[ProfileName]string{
+		"a":       "profile inheritance cycle: a -> b -> a",
+		"selfish": "profile inheritance cycle: selfish -> selfish",
+		"orphan":  "profile orphan extends missing which is not defined",
+	}
+	for name, expected := range tests {
+		if _, _, err := config.ResolveProfile(name); err == nil || err.Error() != expected {
+			t.Errorf("%v: expected error %q, got %v", name, expected, err)
+		}
+	}
+
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "profile inheritance cycle") {
+		t.Errorf("expected Validate() to report cycle, got %v", err)
+	}
+}
+
+// TestUpdateProfileStoresOnlyOverrides tests that inherited settings aren't copied into child profile.
+func TestUpdateProfileStoresOnlyOverrides(t *testing.T) {
+	config := inheritanceConfig()
+
+	child := config.GetProfile("child")
+	if err := child.AddRepository("cli"); err != nil {
+		t.Fatalf("AddRepository() failed: %s", err)
+	}
+	if err := config.UpdateProfile(child); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+
+	stored := config.(*issuectlConfig).Profiles["child"]
+	if stored.GitUserName != "" || stored.IssueBackend != "" {
+		t.Errorf("inherited settings stored in child profile: %+v", stored)
+	}
+	// web is inherited, but child lists it explicitly
+	if expected := []RepoConfigName{"web", "docs", "cli"}; !reflect.DeepEqual(stored.Repositories, expected) {
+		t.Errorf("expected stored repositories %v, got %v", expected, stored.Repositories)
+	}
+
+	// change of parent has to be visible in child
+	base := config.GetProfile("base")
+	base.GitUserName = "other"
+	if err := config.UpdateProfile(base); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+	if gitUser := config.GetProfile("child").GitUserName; gitUser != "other" {
+		t.Errorf("expected child to inherit updated git user, got %v", gitUser)
+	}
+}
+
+// TestUpdateProfileKeepsExplicitOverrides tests that override equal to inherited value doesn't start following parent.
+func TestUpdateProfileKeepsExplicitOverrides(t *testing.T) {
+	config := inheritanceConfig()
+	base := config.GetProfile("base")
+	base.WorkDir = "/child"
+	if err := config.UpdateProfile(base); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+
+	// child overrides workDir with the same value parent has now
+	if err := config.UpdateProfile(config.GetProfile("child")); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+	base.WorkDir = "/elsewhere"
+	if err := config.UpdateProfile(base); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+	if workDir := config.GetProfile("child").WorkDir; workDir != "/child" {
+		t.Errorf("expected child to keep its workDir, got %v", workDir)
+	}
+}
+
+// TestGetProfileReturnsCopy tests that changes of returned profile aren't visible until UpdateProfile.
+func TestGetProfileReturnsCopy(t *testing.T) {
+	config := inheritanceConfig()
+	base := config.GetProfile("base")
+	base.Hooks = Hooks{HookPostStart: {{Run: "make setup"}}}
+	base.Launcher = &LauncherConfig{Command: "code", Args: []string{"{{.Dir}}"}}
+	if err := config.UpdateProfile(base); err != nil {
+		t.Fatalf("UpdateProfile() failed: %s", err)
+	}
+
+	for _, name := range []ProfileName{"base", "child"} {
+		profile := config.GetProfile(name)
+		profile.WorkDir = "/changed"
+		profile.Repositories[0] = "changed"
+		profile.Hooks[HookPostStart][0].Run = "changed"
+		profile.Launcher.Args[0] = "changed"
+	}
+
+	for _, name := range []ProfileName{"base", "child"} {
+		profile := config.GetProfile(name)
+		if profile.WorkDir == "/changed" || profile.Repositories[0] == "changed" {
+			t.Errorf("changes of returned profile %v stored without UpdateProfile", name)
+		}
+		if profile.Hooks[HookPostStart][0].Run != "make setup" || profile.Launcher.Args[0] != "{{.Dir}}" {
+			t.Errorf("nested settings of profile %v shared with returned copy", name)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package issuectl
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectConfigFileName is name of optional config file kept in repository
+const ProjectConfigFileName = ".issuectl.yaml"
+
+// ProjectConfig holds repository specific settings overriding ones from Profile.
+// Settings are applied in order: profile, .issuectl.yaml of cloned repository,
+// .issuectl.yaml of current directory and finally CLI flags.
+type ProjectConfig struct {
+	BaseBranch     string `yaml:"baseBranch,omitempty"`
+	BranchTemplate string `yaml:"branchTemplate,omitempty"`
+	PRTemplate     string `yaml:"prTemplate,omitempty"`
+	SyncStrategy   string `yaml:"syncStrategy,omitempty"`
+	CommitTemplate string `yaml:"commitTemplate,omitempty"`
+	// Hooks are run as hooks of cloned repository, only when allowProjectHooks of the repository is set,
+	// as file comes from the repository and anyone who can push to it could run commands otherwise
+	Hooks Hooks `yaml:"hooks,omitempty"`
+}
+
+// LoadProjectConfig reads .issuectl.yaml from dir, returns nil if dir has none
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	path := filepath.Join(dir, ProjectConfigFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+	project := &ProjectConfig{}
+	if err := unmarshalConfigStrict(path, data, project); err != nil {
+		return nil, err
+	}
+	if problems := validateHooks(project.Hooks); len(problems) > 0 {
+		return nil, fmt.Errorf("%v %v", path, strings.Join(problems, ", "))
+	}
+	return project, nil
+}
+
+// FindProjectDir looks for .issuectl.yaml in dir and its parents, stopping at root of git repository.
+// Returns empty string when there's none.
+func FindProjectDir(dir string) string {
+	for {
+		if fileExists(filepath.Join(dir, ProjectConfigFileName)) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir || fileExists(filepath.Join(dir, ".git")) {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Apply overwrites profile settings with ones set in project config
+func (p *ProjectConfig) Apply(profile *Profile) {
+	if p.BaseBranch != "" {
+		profile.BaseBranch = p.BaseBranch
+	}
+	if p.BranchTemplate != "" {
+		profile.BranchTemplate = p.BranchTemplate
+	}
+	if p.PRTemplate != "" {
+		profile.PRTemplate = p.PRTemplate
+	}
+	if p.SyncStrategy != "" {
+		profile.SyncStrategy = p.SyncStrategy
+	}
+	if p.CommitTemplate != "" {
+		profile.CommitTemplate = p.CommitTemplate
+	}
+}
+
+// EffectiveProfile returns copy of profile with project configs from dirs applied in order.
+// Empty dirs are skipped.
+func EffectiveProfile(profile *Profile, dirs ...string) (*Profile, error) {
+	effective := *profile
+	effective.Repositories = append([]RepoConfigName{}, profile.Repositories...)
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		project, err := LoadProjectConfig(dir)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil {
+			Log.V(3).Infof("Applying project config from %v", dir)
+			project.Apply(&effective)
+		}
+	}
+	return &effective, nil
+}
+
+// currentProjectDir returns directory of project config applying to current working directory
+func currentProjectDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return FindProjectDir(dir)
+}
+
+// EffectiveConfig returns copy of config with current profile replaced by effective one
+// for current working directory.
+func EffectiveConfig(config IssuectlConfig) (IssuectlConfig, error) {
+	ic, ok := config.(*issuectlConfig)
+	if !ok {
+		return nil, fmt.Errorf("unsupported config implementation %T", config)
+	}
+	profile := ic.GetProfile(ic.CurrentProfile)
+	if profile == nil {
+		return config, nil
+	}
+	effective, err := EffectiveProfile(profile, currentProjectDir())
+	if err != nil {
+		return nil, err
+	}
+
+	copied := *ic
+	copied.Profiles = map[ProfileName]*Profile{}
+	for name, p := range ic.Profiles {
+		copied.Profiles[name] = p
+	}
+	copied.Profiles[effective.Name] = effective
+	return copied.GetInMemory(), nil
+}
@@ -0,0 +1,119 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProjectConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFileName), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write project config: %s", err)
+	}
+}
+
+// TestEffectiveProfile tests that project configs are applied over profile in order.
+func TestEffectiveProfile(t *testing.T) {
+	repoDir := t.TempDir()
+	currentDir := t.TempDir()
+	writeProjectConfig(t, repoDir, "baseBranch: main\nprTemplate: from repo\n")
+	writeProjectConfig(t, currentDir, "baseBranch: develop\n")
+
+	profile := &Profile{Name: "test", BaseBranch: "master", BranchTemplate: "{{.ID}}", PRTemplate: "from profile"}
+	effective, err := EffectiveProfile(profile, repoDir, "", currentDir)
+	if err != nil {
+		t.Fatalf("EffectiveProfile() failed: %s", err)
+	}
+
+	if effective.BaseBranch != "develop" {
+		t.Errorf("expected base branch from current dir, got %v", effective.BaseBranch)
+	}
+	if effective.PRTemplate != "from repo" {
+		t.Errorf("expected PR template from repo, got %v", effective.PRTemplate)
+	}
+	if effective.BranchTemplate != "{{.ID}}" {
+		t.Errorf("expected branch template from profile, got %v", effective.BranchTemplate)
+	}
+	if profile.BaseBranch != "master" {
+		t.Errorf("original profile was modified")
+	}
+}
+
+// TestLoadProjectConfigRejectsUnknownFields tests that typos in project config are reported.
+func TestLoadProjectConfigRejectsUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	writeProjectConfig(t, dir, "basebranch: main\n")
+
+	_, err := LoadProjectConfig(dir)
+	if err == nil || !strings.Contains(err.Error(), `did you mean "baseBranch"?`) {
+		t.Errorf("expected unknown field error with suggestion, got %v", err)
+	}
+}
+
+// TestFindProjectDir tests lookup of project config in parent directories.
+func TestFindProjectDir(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "pkg", "nested")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("failed to create dirs: %s", err)
+	}
+	writeProjectConfig(t, root, "baseBranch: outside\n")
+
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %s", err)
+	}
+	if dir := FindProjectDir(nested); dir != "" {
+		t.Errorf("expected lookup to stop at repository root, found %v", dir)
+	}
+
+	writeProjectConfig(t, repo, "baseBranch: main\n")
+	if dir := FindProjectDir(nested); dir != repo {
+		t.Errorf("expected project dir %v, got %v", repo, dir)
+	}
+}
+
+// TestProjectConfigOverrides tests that branch, base branch and PR templates are used in issue lifecycle.
+func TestProjectConfigOverrides(t *testing.T) {
+	server := newAPIServer(t, "github/project_config")
+	backend := githubFixtureBackend(server)
+	env := newTestEnv(t, backend, backend, "service")
+	env.updateProfile(t, func(profile *Profile) { profile.BranchTemplate = "feature/{{.ID}}-{{.Title}}" })
+	ctx := context.Background()
+
+	if err := StartWorkingOnIssue(ctx, "", env.config, "42"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ := env.config.GetIssue("42")
+	if issue.BranchName != "feature/42-Fix-login-bug" {
+		t.Errorf("expected branch from template, got %v", issue.BranchName)
+	}
+
+	writeProjectConfig(t, filepath.Join(issue.Dir, "service"), "baseBranch: develop\nprTemplate: \"Closes #{{.ID}} from {{.Branch}}\"\n")
+	if err := OpenPullRequest(ctx, env.config, "42", ""); err != nil {
+		t.Fatalf("OpenPullRequest() failed: %s", err)
+	}
+}
+
+// TestStartUsesBaseBranchOfClone tests that issue branch starts at baseBranch set in .issuectl.yaml of cloned repository.
+func TestStartUsesBaseBranchOfClone(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	env.repos["service"].commit(t, "develop", nil)
+	env.repos["service"].commit(t, "master", map[string]string{ProjectConfigFileName: "baseBranch: develop\n"})
+
+	ctx := context.Background()
+	if err := StartWorkingOnIssue(ctx, "", env.config, "42"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ := env.config.GetIssue("42")
+	commit, err := execGit{}.LastCommit(ctx, filepath.Join(issue.Dir, "service"))
+	if err != nil {
+		t.Fatalf("LastCommit() failed: %s", err)
+	}
+	if commit.Subject != "Update develop" {
+		t.Errorf("expected issue branch to start at develop, got %v", commit)
+	}
+}
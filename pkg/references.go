@@ -0,0 +1,112 @@
+package issuectl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InUseError is returned when deleting config entity still referenced by other entities
+type InUseError struct {
+	Entity string
+	Name   string
+	UsedBy []string
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("%v %v is still used by %v", e.Entity, e.Name, strings.Join(e.UsedBy, ", "))
+}
+
+// checkNotInUse returns InUseError when usedBy isn't empty
+func checkNotInUse(entity, name string, usedBy []string) error {
+	if len(usedBy) == 0 {
+		return nil
+	}
+	sort.Strings(usedBy)
+	return &InUseError{Entity: entity, Name: name, UsedBy: usedBy}
+}
+
+// resolvedProfiles returns profiles with settings inherited through extends applied,
+// so that entities used only by parent profile count as used by its children too
+func (ic *issuectlConfig) resolvedProfiles() []*Profile {
+	profiles := []*Profile{}
+	for name, profile := range ic.Profiles {
+		resolved, _, err := resolveProfile(ic.Profiles, name)
+		if err != nil {
+			resolved = profile
+		}
+		profiles = append(profiles, resolved)
+	}
+	return profiles
+}
+
+// backendUsers lists profiles and issues referencing backend
+func (ic *issuectlConfig) backendUsers(name BackendConfigName) []string {
+	usedBy := []string{}
+	for _, profile := range ic.resolvedProfiles() {
+		if profile.IssueBackend == name || profile.RepoBackend == name {
+			usedBy = append(usedBy, fmt.Sprintf("profile %v", profile.Name))
+		}
+	}
+	for _, issue := range ic.Issues {
+		if issue.IssueBackend == name || issue.RepoBackend == name {
+			usedBy = append(usedBy, fmt.Sprintf("issue %v", issue.ID))
+		}
+	}
+	return usedBy
+}
+
+// repositoryUsers lists profiles and issues referencing repository
+func (ic *issuectlConfig) repositoryUsers(name RepoConfigName) []string {
+	usedBy := []string{}
+	for _, profile := range ic.resolvedProfiles() {
+		if profile.DefaultRepository == name || containsRepository(profile.Repositories, name) {
+			usedBy = append(usedBy, fmt.Sprintf("profile %v", profile.Name))
+		}
+	}
+	for _, issue := range ic.Issues {
+		if containsRepository(issue.Repositories, name) {
+			usedBy = append(usedBy, fmt.Sprintf("issue %v", issue.ID))
+		}
+	}
+	return usedBy
+}
+
+// gitUserUsers lists profiles referencing git user
+func (ic *issuectlConfig) gitUserUsers(name GitUserName) []string {
+	usedBy := []string{}
+	for _, profile := range ic.resolvedProfiles() {
+		if profile.GitUserName == name {
+			usedBy = append(usedBy, fmt.Sprintf("profile %v", profile.Name))
+		}
+	}
+	return usedBy
+}
+
+// profileUsers lists issues and profiles referencing profile
+func (ic *issuectlConfig) profileUsers(name ProfileName) []string {
+	usedBy := []string{}
+	if ic.CurrentProfile == name {
+		usedBy = append(usedBy, "current profile setting")
+	}
+	for _, profile := range ic.Profiles {
+		if profile.Extends == name {
+			usedBy = append(usedBy, fmt.Sprintf("profile %v", profile.Name))
+		}
+	}
+	for _, issue := range ic.Issues {
+		if issue.Profile == name {
+			usedBy = append(usedBy, fmt.Sprintf("issue %v", issue.ID))
+		}
+	}
+	return usedBy
+}
+
+func containsRepository(repositories []RepoConfigName, name RepoConfigName) bool {
+	for _, repo := range repositories {
+		if repo == name {
+			return true
+		}
+	}
+	return false
+}
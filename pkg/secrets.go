@@ -0,0 +1,201 @@
+package issuectl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// SecretSource tells where backend token is read from instead of the config file.
+// Exactly one of the fields should be set.
+type SecretSource struct {
+	// Env is a name of environment variable holding the token
+	Env string `yaml:"env,omitempty"`
+	// Command is a shell command printing the token, e.g. `pass show jira` or `op read op://work/jira/token`
+	Command string `yaml:"command,omitempty"`
+	// GitCredential is URL for which token is requested from configured git credential helper
+	GitCredential string `yaml:"gitCredential,omitempty"`
+	// Vault is a key under which token is stored in encrypted issuectl vault
+	Vault string `yaml:"vault,omitempty"`
+}
+
+// Validate checks that exactly one source is configured
+func (s *SecretSource) Validate() error {
+	set := 0
+	for _, value := range []string{s.Env, s.Command, s.GitCredential, s.Vault} {
+		if value != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("token source has to define exactly one of env, command, gitCredential or vault")
+	}
+	return nil
+}
+
+// Resolve reads secret from the source
+func (s *SecretSource) Resolve(ctx context.Context) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	switch {
+	case s.Env != "":
+		secret, found := os.LookupEnv(s.Env)
+		if !found || secret == "" {
+			return "", fmt.Errorf("environment variable %v is not set", s.Env)
+		}
+		return secret, nil
+
+	case s.Command != "":
+		return runSecretCommand(ctx, s.Command)
+
+	case s.GitCredential != "":
+		return getGitCredential(ctx, s.GitCredential)
+
+	default:
+		vault, err := OpenVault(DefaultVaultFilePath)
+		if err != nil {
+			return "", err
+		}
+		secret, found := vault.Get(s.Vault)
+		if !found {
+			return "", fmt.Errorf("secret %v not found in vault", s.Vault)
+		}
+		return secret, nil
+	}
+}
+
+// runSecretCommand runs command in shell and returns its trimmed output
+func runSecretCommand(ctx context.Context, command string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = os.Stdin
+	if err := runCommand(ctx, cmd); err != nil {
+		return "", fmt.Errorf("token command failed: %w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+	secret := strings.TrimSpace(stdout.String())
+	if secret == "" {
+		return "", fmt.Errorf("token command printed nothing")
+	}
+	return secret, nil
+}
+
+// getGitCredential asks git credential helpers for password stored for URL
+func getGitCredential(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid git credential URL %q", rawURL)
+	}
+
+	request := fmt.Sprintf("protocol=%v\nhost=%v\n", u.Scheme, u.Host)
+	if path := strings.TrimPrefix(u.Path, "/"); path != "" {
+		request += fmt.Sprintf("path=%v\n", path)
+	}
+	if u.User != nil {
+		request += fmt.Sprintf("username=%v\n", u.User.Username())
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(request + "\n")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// never fall back to interactive prompt
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err := runCommand(ctx, cmd); err != nil {
+		return "", fmt.Errorf("git credential fill failed: %w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		if password, found := strings.CutPrefix(scanner.Text(), "password="); found && password != "" {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("git credential helper has no password for %v", u.Host)
+}
+
+// getStoredToken returns token stored directly in backend config
+func (b *BackendConfig) getStoredToken() string {
+	switch b.Type {
+	case BackendGithub:
+		if b.GitHub != nil {
+			return b.GitHub.Token
+		}
+	case BackendGitLab:
+		if b.GitLab != nil {
+			return b.GitLab.Token
+		}
+	case BackendJira:
+		if b.Jira != nil {
+			return b.Jira.Token
+		}
+	}
+	return ""
+}
+
+// HasStoredToken checks if backend keeps its token in the config file
+func (b *BackendConfig) HasStoredToken() bool {
+	return b.getStoredToken() != ""
+}
+
+// ClearStoredToken removes token stored directly in backend config. Tokens are removed from every settings block,
+// not only the one matching backend type, so that none is left behind e.g. in exported bundle.
+func (b *BackendConfig) ClearStoredToken() {
+	if b.GitHub != nil {
+		b.GitHub.Token = ""
+	}
+	if b.GitLab != nil {
+		b.GitLab.Token = ""
+	}
+	if b.Jira != nil {
+		b.Jira.Token = ""
+	}
+}
+
+// EncodeToken encodes token for storing it in config file
+func EncodeToken(token string) string {
+	return base64.RawStdEncoding.EncodeToString([]byte(token))
+}
+
+// ResolveBackendToken returns token for backend, read from its TokenSource or config file
+func ResolveBackendToken(ctx context.Context, backendConfig *BackendConfig) (string, error) {
+	if backendConfig.TokenSource != nil {
+		token, err := backendConfig.TokenSource.Resolve(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get token for backend %v: %w", backendConfig.Name, err)
+		}
+		return token, nil
+	}
+
+	token, err := base64.RawStdEncoding.DecodeString(backendConfig.getStoredToken())
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token of backend %v: %w", backendConfig.Name, err)
+	}
+	return string(token), nil
+}
+
+// MoveTokenToVault moves token stored in backend config to the vault and points backend to it
+func MoveTokenToVault(ctx context.Context, vault *Vault, backendConfig *BackendConfig) error {
+	if !backendConfig.HasStoredToken() {
+		return nil
+	}
+	token, err := ResolveBackendToken(ctx, backendConfig)
+	if err != nil {
+		return err
+	}
+	key := string(backendConfig.Name)
+	vault.Set(key, token)
+	backendConfig.TokenSource = &SecretSource{Vault: key}
+	backendConfig.ClearStoredToken()
+	return nil
+}
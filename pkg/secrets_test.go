@@ -0,0 +1,145 @@
+package issuectl
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempVault points DefaultVaultFilePath to file in temporary directory and sets passphrase
+func useTempVault(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".issuectl.vault")
+	previous := DefaultVaultFilePath
+	DefaultVaultFilePath = path
+	cachedPassphrase = ""
+	t.Setenv(EnvVaultPassphrase, "correct horse")
+	t.Cleanup(func() {
+		DefaultVaultFilePath = previous
+		cachedPassphrase = ""
+	})
+	return path
+}
+
+// TestResolveBackendToken tests reading tokens from supported sources.
+func TestResolveBackendToken(t *testing.T) {
+	t.Setenv("ISSUECTL_TEST_TOKEN", "env-token")
+
+	tests := []struct {
+		name     string
+		backend  *BackendConfig
+		expected string
+	}{
+		{
+			name:     "stored in config",
+			backend:  &BackendConfig{Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{Token: EncodeToken("stored-token")}},
+			expected: "stored-token",
+		},
+		{
+			name: "environment variable",
+			backend: &BackendConfig{Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{Token: EncodeToken("ignored")},
+				TokenSource: &SecretSource{Env: "ISSUECTL_TEST_TOKEN"}},
+			expected: "env-token",
+		},
+		{
+			name: "command",
+			backend: &BackendConfig{Name: "jira", Type: BackendJira, Jira: &JiraConfig{},
+				TokenSource: &SecretSource{Command: "echo command-token"}},
+			expected: "command-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := ResolveBackendToken(context.Background(), tt.backend)
+			if err != nil {
+				t.Fatalf("ResolveBackendToken() failed: %s", err)
+			}
+			if token != tt.expected {
+				t.Errorf("expected token %q, got %q", tt.expected, token)
+			}
+		})
+	}
+}
+
+// TestResolveBackendTokenErrors tests errors of misconfigured token sources.
+func TestResolveBackendTokenErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *SecretSource
+		expected string
+	}{
+		{name: "missing env", source: &SecretSource{Env: "ISSUECTL_TEST_MISSING"}, expected: "is not set"},
+		{name: "failing command", source: &SecretSource{Command: "exit 1"}, expected: "token command failed"},
+		{name: "no source", source: &SecretSource{}, expected: "exactly one"},
+		{name: "many sources", source: &SecretSource{Env: "A", Vault: "b"}, expected: "exactly one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &BackendConfig{Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{}, TokenSource: tt.source}
+			_, err := ResolveBackendToken(context.Background(), backend)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestVaultRoundTrip tests that secrets survive save and are protected by passphrase.
+func TestVaultRoundTrip(t *testing.T) {
+	path := useTempVault(t)
+
+	vault, err := OpenVault(path)
+	if err != nil {
+		t.Fatalf("OpenVault() failed: %s", err)
+	}
+	vault.Set("github", "secret-token")
+	if err := vault.Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	cachedPassphrase = ""
+	reopened, err := OpenVault(path)
+	if err != nil {
+		t.Fatalf("OpenVault() failed: %s", err)
+	}
+	if secret, found := reopened.Get("github"); !found || secret != "secret-token" {
+		t.Errorf("expected secret-token, got %q", secret)
+	}
+
+	cachedPassphrase = ""
+	t.Setenv(EnvVaultPassphrase, "wrong")
+	if _, err := OpenVault(path); err == nil {
+		t.Errorf("expected error when opening vault with wrong passphrase")
+	}
+}
+
+// TestMoveTokenToVault tests migration of token stored in config to vault.
+func TestMoveTokenToVault(t *testing.T) {
+	path := useTempVault(t)
+	vault, err := OpenVault(path)
+	if err != nil {
+		t.Fatalf("OpenVault() failed: %s", err)
+	}
+
+	backend := &BackendConfig{Name: "gl", Type: BackendGitLab, GitLab: &GitLabConfig{Token: EncodeToken("gitlab-token")}}
+	if err := MoveTokenToVault(context.Background(), vault, backend); err != nil {
+		t.Fatalf("MoveTokenToVault() failed: %s", err)
+	}
+	if err := vault.Save(); err != nil {
+		t.Fatalf("Save() failed: %s", err)
+	}
+
+	if backend.HasStoredToken() {
+		t.Errorf("expected token to be removed from backend config")
+	}
+	token, err := ResolveBackendToken(context.Background(), backend)
+	if err != nil {
+		t.Fatalf("ResolveBackendToken() failed: %s", err)
+	}
+	if token != "gitlab-token" {
+		t.Errorf("expected token from vault, got %q", token)
+	}
+}
@@ -0,0 +1,332 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Terminal multiplexers issue sessions can be run in
+const (
+	MultiplexerTmux   = "tmux"
+	MultiplexerZellij = "zellij"
+)
+
+// sessionWindowDefaults is a key of window settings applied to repositories without their own
+const sessionWindowDefaults RepoConfigName = "*"
+
+// SessionConfig describes terminal multiplexer session started for issue, with one window per repository
+type SessionConfig struct {
+	// Multiplexer is tmux or zellij [defaults to tmux]
+	Multiplexer string `yaml:"multiplexer,omitempty"`
+	// Windows configure window of each repository, settings under "*" apply to repositories without own entry
+	Windows map[RepoConfigName]*SessionWindow `yaml:"windows,omitempty"`
+}
+
+// SessionWindow configures panes of repository window
+type SessionWindow struct {
+	// Layout of panes, tmux layout like even-horizontal, main-vertical or tiled, for zellij vertical or horizontal
+	Layout string `yaml:"layout,omitempty"`
+	// Panes are startup commands, one pane is opened for each of them [defaults to single pane with shell]
+	Panes []string `yaml:"panes,omitempty"`
+}
+
+// sessionWindow is a window of issue session with settings resolved for repository
+type sessionWindow struct {
+	name   string
+	dir    string
+	layout string
+	panes  []string
+}
+
+// multiplexer manages sessions of terminal multiplexer
+type multiplexer interface {
+	hasSession(ctx context.Context, name string) (bool, error)
+	createSession(ctx context.Context, name, dir string, windows []sessionWindow) error
+	attach(ctx context.Context, name, dir string) error
+	killSession(ctx context.Context, name string) error
+}
+
+// validateSession checks that multiplexer is supported
+func validateSession(session *SessionConfig) error {
+	if session == nil {
+		return nil
+	}
+	_, err := getMultiplexer(session.Multiplexer)
+	return err
+}
+
+func getMultiplexer(name string) (multiplexer, error) {
+	switch name {
+	case "", MultiplexerTmux:
+		return tmux{}, nil
+	case MultiplexerZellij:
+		return zellij{}, nil
+	}
+	return nil, fmt.Errorf("unsupported multiplexer %v, use %v or %v", name, MultiplexerTmux, MultiplexerZellij)
+}
+
+// SessionName returns name of multiplexer session of issue, characters tmux doesn't allow are replaced
+func SessionName(issueID IssueID) string {
+	return strings.NewReplacer(".", "_", ":", "_", " ", "_").Replace(string(issueID))
+}
+
+// issueSession returns session settings of issue profile, defaults when profile has none
+func issueSession(config IssuectlConfig, issue *IssueConfig) *SessionConfig {
+	if profile := config.GetProfile(issue.Profile); profile != nil && profile.Session != nil {
+		return profile.Session
+	}
+	return &SessionConfig{}
+}
+
+// sessionWindows returns window of each issue repository, or single window in issue directory when it has none
+func sessionWindows(session *SessionConfig, issue *IssueConfig) []sessionWindow {
+	settings := func(repo RepoConfigName) *SessionWindow {
+		if window := session.Windows[repo]; window != nil {
+			return window
+		}
+		if window := session.Windows[sessionWindowDefaults]; window != nil {
+			return window
+		}
+		return &SessionWindow{}
+	}
+
+	windows := []sessionWindow{}
+	for _, repo := range issue.Repositories {
+		window := settings(repo)
+		windows = append(windows, sessionWindow{
+			name:   string(repo),
+			dir:    filepath.Join(issue.Dir, string(repo)),
+			layout: window.Layout,
+			panes:  window.Panes,
+		})
+	}
+	if len(windows) == 0 {
+		window := settings(sessionWindowDefaults)
+		windows = append(windows, sessionWindow{name: issue.Name, dir: issue.Dir, layout: window.Layout, panes: window.Panes})
+	}
+	return windows
+}
+
+// OpenIssueSession attaches to multiplexer session of issue, creating it with window per repository when it doesn't exist
+func OpenIssueSession(ctx context.Context, config IssuectlConfig, issueID IssueID) error {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return fmt.Errorf("issue %v not found", issueID)
+	}
+	session := issueSession(config, issue)
+	mux, err := getMultiplexer(session.Multiplexer)
+	if err != nil {
+		return err
+	}
+
+	name := SessionName(issueID)
+	exists, err := mux.hasSession(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		Log.Infofp("🪟", "Creating session %v", name)
+		if err := mux.createSession(ctx, name, issue.Dir, sessionWindows(session, issue)); err != nil {
+			return err
+		}
+	}
+	Log.Infofp("🔗", "Attaching to session %v", name)
+	return mux.attach(ctx, name, issue.Dir)
+}
+
+// KillIssueSession stops multiplexer session of issue if it's running
+func KillIssueSession(ctx context.Context, config IssuectlConfig, issue *IssueConfig) error {
+	mux, err := getMultiplexer(issueSession(config, issue).Multiplexer)
+	if err != nil {
+		return err
+	}
+	name := SessionName(issue.ID)
+	exists, err := mux.hasSession(ctx, name)
+	if err != nil || !exists {
+		return err
+	}
+	Log.Infofp("🪟", "Killing session %v", name)
+	return mux.killSession(ctx, name)
+}
+
+// runMultiplexer runs multiplexer command and returns its output
+func runMultiplexer(ctx context.Context, name string, args ...string) (string, error) {
+	Log.V(3).Infof("%v %v", name, strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, name, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%v %v failed: %s", name, args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%v %v failed: %w", name, args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// attachMultiplexer runs multiplexer command attached to terminal
+func attachMultiplexer(ctx context.Context, name string, args ...string) error {
+	Log.V(3).Infof("%v %v", name, strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to attach to %v session: %w", name, err)
+	}
+	return nil
+}
+
+type tmux struct{}
+
+func (tmux) hasSession(ctx context.Context, name string) (bool, error) {
+	if _, err := exec.LookPath(MultiplexerTmux); err != nil {
+		return false, nil
+	}
+	// has-session fails both for missing session and when tmux server isn't running
+	_, err := runMultiplexer(ctx, MultiplexerTmux, "has-session", "-t", "="+name)
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	return err == nil, nil
+}
+
+func (tmux) createSession(ctx context.Context, name, dir string, windows []sessionWindow) error {
+	for i, window := range windows {
+		// windows are addressed by ids, repository names can contain characters special in tmux targets
+		args := []string{"new-window", "-t", "=" + name + ":", "-n", window.name, "-c", window.dir, "-P", "-F", "#{window_id}"}
+		if i == 0 {
+			args = []string{"new-session", "-d", "-s", name, "-n", window.name, "-c", window.dir, "-P", "-F", "#{window_id}"}
+		}
+		windowID, err := runMultiplexer(ctx, MultiplexerTmux, args...)
+		if err != nil {
+			return err
+		}
+		for j, command := range window.panes {
+			target := windowID
+			if j > 0 {
+				paneID, err := runMultiplexer(ctx, MultiplexerTmux, "split-window", "-t", windowID, "-c", window.dir, "-P", "-F", "#{pane_id}")
+				if err != nil {
+					return err
+				}
+				target = paneID
+			}
+			if command == "" {
+				continue
+			}
+			if _, err := runMultiplexer(ctx, MultiplexerTmux, "send-keys", "-t", target, command, "Enter"); err != nil {
+				return err
+			}
+		}
+		if window.layout != "" {
+			if _, err := runMultiplexer(ctx, MultiplexerTmux, "select-layout", "-t", windowID, window.layout); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := runMultiplexer(ctx, MultiplexerTmux, "select-window", "-t", "="+name+":^")
+	return err
+}
+
+func (tmux) attach(ctx context.Context, name, dir string) error {
+	// inside of tmux attaching would nest sessions, current client is switched instead
+	if os.Getenv("TMUX") != "" {
+		_, err := runMultiplexer(ctx, MultiplexerTmux, "switch-client", "-t", "="+name)
+		return err
+	}
+	return attachMultiplexer(ctx, MultiplexerTmux, "attach-session", "-t", "="+name)
+}
+
+func (tmux) killSession(ctx context.Context, name string) error {
+	_, err := runMultiplexer(ctx, MultiplexerTmux, "kill-session", "-t", "="+name)
+	return err
+}
+
+type zellij struct{}
+
+func (zellij) hasSession(ctx context.Context, name string) (bool, error) {
+	if _, err := exec.LookPath(MultiplexerZellij); err != nil {
+		return false, nil
+	}
+	// list-sessions fails when there are no sessions
+	output, err := runMultiplexer(ctx, MultiplexerZellij, "list-sessions", "--short", "--no-formatting")
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		return false, nil
+	}
+	for _, session := range strings.Split(output, "\n") {
+		if strings.TrimSpace(session) == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createSession only writes layout of session, zellij creates session when attaching
+func (zellij) createSession(ctx context.Context, name, dir string, windows []sessionWindow) error {
+	return os.WriteFile(zellijLayoutPath(dir, name), []byte(zellijLayout(windows)), 0644)
+}
+
+func (z zellij) attach(ctx context.Context, name, dir string) error {
+	if os.Getenv("ZELLIJ") != "" {
+		return fmt.Errorf("already inside zellij session, detach first to open session %v", name)
+	}
+	exists, err := z.hasSession(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return attachMultiplexer(ctx, MultiplexerZellij, "attach", name)
+	}
+	return attachMultiplexer(ctx, MultiplexerZellij, "--session", name, "--new-session-with-layout", zellijLayoutPath(dir, name))
+}
+
+func (zellij) killSession(ctx context.Context, name string) error {
+	_, err := runMultiplexer(ctx, MultiplexerZellij, "kill-session", name)
+	return err
+}
+
+func zellijLayoutPath(dir, name string) string {
+	return filepath.Join(dir, "."+name+".kdl")
+}
+
+// zellijLayout renders KDL layout with tab per window, startup commands keep shell open after they finish
+func zellijLayout(windows []sessionWindow) string {
+	var layout strings.Builder
+	layout.WriteString("layout {\n")
+	for _, window := range windows {
+		fmt.Fprintf(&layout, "    tab name=%v cwd=%v", strconv.Quote(window.name), strconv.Quote(window.dir))
+		if window.layout == "vertical" || window.layout == "horizontal" {
+			fmt.Fprintf(&layout, " split_direction=%v", strconv.Quote(window.layout))
+		}
+		layout.WriteString(" {\n")
+		if len(window.panes) == 0 {
+			layout.WriteString("        pane\n")
+		}
+		for _, command := range window.panes {
+			if command == "" {
+				layout.WriteString("        pane\n")
+				continue
+			}
+			fmt.Fprintf(&layout, "        pane command=\"sh\" {\n            args \"-c\" %v\n        }\n",
+				strconv.Quote(command+"; exec ${SHELL:-sh}"))
+		}
+		layout.WriteString("    }\n")
+	}
+	layout.WriteString("}\n")
+	return layout.String()
+}
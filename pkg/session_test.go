@@ -0,0 +1,89 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSessionWindows tests that windows get settings of their repository or defaults.
+func TestSessionWindows(t *testing.T) {
+	session := &SessionConfig{Windows: map[RepoConfigName]*SessionWindow{
+		"api": {Layout: "even-horizontal", Panes: []string{"make run", "make test"}},
+		"*":   {Panes: []string{"git status"}},
+	}}
+	issue := &IssueConfig{Name: "XY-69", Dir: "/work/XY-69", Repositories: []RepoConfigName{"api", "web"}}
+
+	expected := []sessionWindow{
+		{name: "api", dir: "/work/XY-69/api", layout: "even-horizontal", panes: []string{"make run", "make test"}},
+		{name: "web", dir: "/work/XY-69/web", panes: []string{"git status"}},
+	}
+	if windows := sessionWindows(session, issue); !reflect.DeepEqual(windows, expected) {
+		t.Errorf("expected windows %+v, got %+v", expected, windows)
+	}
+
+	layout := zellijLayout(expected)
+	for _, part := range []string{`tab name="api" cwd="/work/XY-69/api" {`, `args "-c" "make test; exec ${SHELL:-sh}"`} {
+		if !strings.Contains(layout, part) {
+			t.Errorf("expected zellij layout to contain %q, got\n%v", part, layout)
+		}
+	}
+	if SessionName("XY-69.1") != "XY-69_1" {
+		t.Errorf("expected dots to be replaced in session name, got %v", SessionName("XY-69.1"))
+	}
+}
+
+// TestTmuxSession tests creating tmux session with window per repository and killing it on finish.
+func TestTmuxSession(t *testing.T) {
+	if _, err := exec.LookPath(MultiplexerTmux); err != nil {
+		t.Skip("tmux not installed")
+	}
+	// private tmux server, so that sessions of user aren't touched
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	issue := &IssueConfig{ID: "XY-69", Name: "XY-69", Dir: dir, Repositories: []RepoConfigName{"api", "web"}, Profile: "work"}
+	for _, repo := range issue.Repositories {
+		if err := os.MkdirAll(filepath.Join(dir, string(repo)), 0755); err != nil {
+			t.Fatalf("failed to create repo dir: %s", err)
+		}
+	}
+	session := &SessionConfig{Windows: map[RepoConfigName]*SessionWindow{
+		"api": {Layout: "even-horizontal", Panes: []string{"", "echo test"}},
+	}}
+	config := GetConfig("work", nil, nil, nil, map[ProfileName]*Profile{
+		"work": {Name: "work", Session: session},
+	}).GetInMemory()
+	if err := config.AddIssue(issue); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+
+	mux := tmux{}
+	if err := mux.createSession(ctx, "XY-69", dir, sessionWindows(session, issue)); err != nil {
+		t.Fatalf("createSession() failed: %s", err)
+	}
+	windows, err := runMultiplexer(ctx, "tmux", "list-windows", "-t", "=XY-69", "-F", "#{window_name}:#{window_panes}")
+	if err != nil {
+		t.Fatalf("failed to list windows: %s", err)
+	}
+	if windows != "api:2\nweb:1" {
+		t.Errorf("expected api window with 2 panes and web window, got %q", windows)
+	}
+
+	if err := KillIssueSession(ctx, config, issue); err != nil {
+		t.Fatalf("KillIssueSession() failed: %s", err)
+	}
+	if exists, _ := mux.hasSession(ctx, "XY-69"); exists {
+		t.Errorf("session still running after kill")
+	}
+	if err := KillIssueSession(ctx, config, issue); err != nil {
+		t.Errorf("KillIssueSession() without session failed: %s", err)
+	}
+}
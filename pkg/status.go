@@ -0,0 +1,189 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// PullRequestStatus is state of pull request opened from issue branch with its CI checks
+type PullRequestStatus struct {
+	Number int    `yaml:"number"`
+	URL    string `yaml:"url,omitempty"`
+	// State is open, closed or merged
+	State  string        `yaml:"state"`
+	Checks []CheckStatus `yaml:"checks,omitempty"`
+}
+
+// CheckStatus is result of single CI check or job
+type CheckStatus struct {
+	Name string `yaml:"name"`
+	// State is reported by backend, e.g. queued, in_progress, pending, running, success or failure
+	State string `yaml:"state"`
+	URL   string `yaml:"url,omitempty"`
+}
+
+// PullRequestStatusReader finds pull request opened from branch, implemented by repository backends supporting it
+type PullRequestStatusReader interface {
+	// GetPullRequestStatus returns nil when there is no pull request for headBranch
+	GetPullRequestStatus(ctx context.Context, owner string, repo RepoConfigName, headBranch string) (*PullRequestStatus, error)
+}
+
+// BranchComparison counts commits of HEAD and Ref missing from each other
+type BranchComparison struct {
+	Ref    string `yaml:"ref"`
+	Ahead  int    `yaml:"ahead"`
+	Behind int    `yaml:"behind"`
+}
+
+// RepositoryStatus describes state of repository cloned for issue
+type RepositoryStatus struct {
+	Name     RepoConfigName    `yaml:"name"`
+	Dir      string            `yaml:"dir"`
+	Branch   string            `yaml:"branch,omitempty"`
+	Upstream *BranchComparison `yaml:"upstream,omitempty"`
+	Base     *BranchComparison `yaml:"base,omitempty"`
+	// DirtyFiles are lines of git status --porcelain
+	DirtyFiles  []string           `yaml:"dirtyFiles"`
+	LastCommit  string             `yaml:"lastCommit,omitempty"`
+	PullRequest *PullRequestStatus `yaml:"pullRequest,omitempty"`
+	// Errors lists parts of status which couldn't be read
+	Errors []string `yaml:"errors,omitempty"`
+}
+
+// IssueReport is status of every repository of issue
+type IssueReport struct {
+	ID           IssueID             `yaml:"id"`
+	Name         string              `yaml:"name"`
+	Branch       string              `yaml:"branch"`
+	Repositories []*RepositoryStatus `yaml:"repositories"`
+}
+
+// GetIssueReport reads git status of issue repositories and state of their pull requests.
+// With fetch remotes are fetched first, so ahead/behind counts are current.
+// Problems with single repository or backend are reported in its status instead of failing whole report.
+func GetIssueReport(ctx context.Context, config IssuectlConfig, issueID IssueID, fetch bool) (*IssueReport, error) {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return nil, fmt.Errorf("issue %v not found", issueID)
+	}
+	profile, err := getIssueProfile(config, issue)
+	if err != nil {
+		return nil, err
+	}
+
+	var prReader PullRequestStatusReader
+	var prReaderErr error
+	backendName := issue.RepoBackend
+	if backendName == "" {
+		backendName = profile.RepoBackend
+	}
+	if backendName != "" {
+		repoBackend, err := getRepoBackendConfigurator(ctx, config.GetBackend(backendName))
+		if err != nil {
+			prReaderErr = err
+		} else if reader, ok := repoBackend.(PullRequestStatusReader); ok {
+			prReader = reader
+		}
+	}
+
+	git, err := profileGitClient(profile)
+	if err != nil {
+		return nil, err
+	}
+	gitUser, _ := config.GetGitUser(profile.GitUserName)
+
+	report := &IssueReport{ID: issue.ID, Name: issue.Name, Branch: issue.BranchName}
+	for _, repoName := range issue.Repositories {
+		dir := filepath.Join(issue.Dir, string(repoName))
+		status := getRepositoryStatus(ctx, git, gitUser, profile, dir, repoName, fetch)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		repo := config.GetRepository(repoName)
+		switch {
+		case prReaderErr != nil:
+			status.Errors = append(status.Errors, fmt.Sprintf("pull request: %v", prReaderErr))
+		case prReader != nil && repo != nil && status.Branch != "":
+			pr, err := prReader.GetPullRequestStatus(ctx, repo.Owner, repoName, status.Branch)
+			if err != nil {
+				status.Errors = append(status.Errors, fmt.Sprintf("pull request: %v", err))
+			}
+			status.PullRequest = pr
+		}
+		report.Repositories = append(report.Repositories, status)
+	}
+	return report, nil
+}
+
+// getRepositoryStatus reads branch, divergence from upstream and base branch, changes and last commit of repository in dir
+func getRepositoryStatus(
+	ctx context.Context,
+	git GitClient,
+	gitUser *GitUser,
+	profile *Profile,
+	dir string,
+	name RepoConfigName,
+	fetch bool,
+) *RepositoryStatus {
+	status := &RepositoryStatus{Name: name, Dir: dir, DirtyFiles: []string{}}
+	fail := func(part string, err error) {
+		status.Errors = append(status.Errors, fmt.Sprintf("%v: %v", part, err))
+	}
+	if !fileExists(dir) {
+		fail("repository", fmt.Errorf("directory %v doesn't exist", dir))
+		return status
+	}
+
+	if fetch {
+		if err := git.Fetch(ctx, dir, gitUser); err != nil {
+			fail("fetch", err)
+		}
+	}
+
+	branch, err := git.CurrentBranch(ctx, dir)
+	if err != nil {
+		fail("branch", err)
+		return status
+	}
+	status.Branch = branch
+
+	// branch without upstream isn't a problem, it just wasn't pushed yet
+	if upstream, err := git.Upstream(ctx, dir); err != nil {
+		fail("upstream", err)
+	} else if upstream != "" {
+		if status.Upstream, err = git.Compare(ctx, dir, upstream); err != nil {
+			fail("upstream", err)
+		}
+	}
+
+	// project config of repository can override base branch of profile
+	effective, err := EffectiveProfile(profile, dir)
+	if err != nil {
+		fail("base", err)
+		effective = profile
+	}
+	base := effective.BaseBranch
+	if base == "" {
+		base = defaultBaseBranch
+	}
+	if exists, _ := git.RefExists(ctx, dir, "origin/"+base); exists {
+		base = "origin/" + base
+	}
+	if status.Base, err = git.Compare(ctx, dir, base); err != nil {
+		fail("base", err)
+	}
+
+	if changes, err := git.ChangedFiles(ctx, dir); err != nil {
+		fail("changes", err)
+	} else if len(changes) > 0 {
+		status.DirtyFiles = changes
+	}
+
+	if commit, err := git.LastCommit(ctx, dir); err != nil {
+		fail("last commit", err)
+	} else {
+		status.LastCommit = commit.String()
+	}
+	return status
+}
@@ -0,0 +1,126 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestGetIssueReport tests reporting branch divergence, changes and pull request checks of issue repositories.
+func TestGetIssueReport(t *testing.T) {
+	for _, name := range gitClients {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newAPIServer(t, "github/status")
+			env := newTestEnv(t, nil, nil, "service")
+			env.setGitClient(t, name)
+			if err := StartWorkingOnIssue(ctx, "", env.config, "13"); err != nil {
+				t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+			}
+			issue, _ := env.config.GetIssue("13")
+			repoDir := filepath.Join(issue.Dir, "service")
+
+			if err := os.WriteFile(filepath.Join(repoDir, "feature.go"), []byte("package feature\n"), 0644); err != nil {
+				t.Fatalf("failed to write file: %s", err)
+			}
+			runGitFixture(t, repoDir, "add", "feature.go")
+			runGitFixture(t, repoDir, "commit", "-m", "Add feature")
+			if err := os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("todo\n"), 0644); err != nil {
+				t.Fatalf("failed to write file: %s", err)
+			}
+
+			if err := env.config.AddBackend(githubFixtureBackend(server)); err != nil {
+				t.Fatalf("AddBackend() failed: %s", err)
+			}
+			issue.RepoBackend = "github"
+
+			report, err := GetIssueReport(ctx, env.config, "13", false)
+			if err != nil {
+				t.Fatalf("GetIssueReport() failed: %s", err)
+			}
+			if len(report.Repositories) != 1 {
+				t.Fatalf("expected 1 repository, got %v", len(report.Repositories))
+			}
+			status := report.Repositories[0]
+			if len(status.Errors) > 0 {
+				t.Errorf("unexpected errors: %v", status.Errors)
+			}
+			if status.Branch != issue.BranchName {
+				t.Errorf("expected branch %v, got %v", issue.BranchName, status.Branch)
+			}
+			expectedUpstream := &BranchComparison{Ref: "origin/" + issue.BranchName, Ahead: 1}
+			if !reflect.DeepEqual(status.Upstream, expectedUpstream) {
+				t.Errorf("expected upstream %+v, got %+v", expectedUpstream, status.Upstream)
+			}
+			expectedBase := &BranchComparison{Ref: "origin/master", Ahead: 1}
+			if !reflect.DeepEqual(status.Base, expectedBase) {
+				t.Errorf("expected base %+v, got %+v", expectedBase, status.Base)
+			}
+			if !reflect.DeepEqual(status.DirtyFiles, []string{"?? notes.txt"}) {
+				t.Errorf("unexpected dirty files %q", status.DirtyFiles)
+			}
+			if status.LastCommit == "" {
+				t.Errorf("expected last commit")
+			}
+
+			expectedPR := &PullRequestStatus{
+				Number: 8,
+				URL:    "https://github.com/owner/service/pull/8",
+				State:  "open",
+				Checks: []CheckStatus{{Name: "build", State: "success"}, {Name: "ci/lint", State: "pending"}},
+			}
+			if !reflect.DeepEqual(status.PullRequest, expectedPR) {
+				t.Errorf("expected pull request %+v, got %+v", expectedPR, status.PullRequest)
+			}
+		})
+	}
+}
+
+// TestGetIssueReportMissingRepository tests that repository which isn't cloned is reported instead of failing.
+func TestGetIssueReportMissingRepository(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	issue := &IssueConfig{ID: "7", Name: "7", Dir: filepath.Join(env.workDir, "7"), Profile: "test", Repositories: []RepoConfigName{"service"}}
+	if err := env.config.AddIssue(issue); err != nil {
+		t.Fatalf("AddIssue() failed: %s", err)
+	}
+
+	report, err := GetIssueReport(context.Background(), env.config, "7", false)
+	if err != nil {
+		t.Fatalf("GetIssueReport() failed: %s", err)
+	}
+	if errs := report.Repositories[0].Errors; len(errs) != 1 {
+		t.Errorf("expected missing repository error, got %v", errs)
+	}
+}
+
+// TestGitLabPullRequestStatus tests reading merge request with jobs of its pipeline.
+func TestGitLabPullRequestStatus(t *testing.T) {
+	ctx := context.Background()
+	backend, err := getRepoBackendConfigurator(ctx, gitlabFixtureBackend(newAPIServer(t, "gitlab/status")))
+	if err != nil {
+		t.Fatalf("getRepoBackendConfigurator() failed: %s", err)
+	}
+	reader, ok := backend.(PullRequestStatusReader)
+	if !ok {
+		t.Fatalf("GitLab backend doesn't implement PullRequestStatusReader")
+	}
+
+	status, err := reader.GetPullRequestStatus(ctx, "owner", "service", "13")
+	if err != nil {
+		t.Fatalf("GetPullRequestStatus() failed: %s", err)
+	}
+	expected := &PullRequestStatus{
+		Number: 3,
+		URL:    "https://gitlab.com/owner/service/-/merge_requests/3",
+		State:  "open",
+		Checks: []CheckStatus{
+			{Name: "build", State: "success", URL: "https://gitlab.com/owner/service/-/jobs/901"},
+			{Name: "test", State: "running", URL: "https://gitlab.com/owner/service/-/jobs/902"},
+		},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected merge request %+v, got %+v", expected, status)
+	}
+}
@@ -0,0 +1,174 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Strategies of updating issue branch with changes from base branch
+const (
+	SyncRebase = "rebase"
+	SyncMerge  = "merge"
+)
+
+// validateSyncStrategy checks that strategy is rebase, merge or empty for default
+func validateSyncStrategy(strategy string) error {
+	switch strategy {
+	case "", SyncRebase, SyncMerge:
+		return nil
+	}
+	return fmt.Errorf("invalid syncStrategy %q, use %v or %v", strategy, SyncRebase, SyncMerge)
+}
+
+// SyncIssue fetches every repository of issue and rebases or merges issue branch onto its base branch.
+// Strategy overrides one set in profile and project config. Repositories with conflicts are left
+// with rebase or merge in progress, others are still synced, and all which need attention are listed in returned error.
+// With resume, rebases and merges stopped on conflicts are continued after they were resolved.
+func SyncIssue(ctx context.Context, config IssuectlConfig, issueID IssueID, strategy string, resume bool) error {
+	issue, found := config.GetIssue(issueID)
+	if !found {
+		return fmt.Errorf("issue %v not found", issueID)
+	}
+	profile, err := getIssueProfile(config, issue)
+	if err != nil {
+		return err
+	}
+	if err := validateSyncStrategy(strategy); err != nil {
+		return err
+	}
+	git, err := profileGitClient(profile)
+	if err != nil {
+		return err
+	}
+	syncer, err := gitSyncer(git)
+	if err != nil {
+		return err
+	}
+	gitUser, _ := config.GetGitUser(profile.GitUserName)
+
+	attention := []string{}
+	for _, repoName := range issue.Repositories {
+		dir := filepath.Join(issue.Dir, string(repoName))
+		problem, err := syncRepository(ctx, git, syncer, gitUser, profile, dir, repoName, strategy, resume)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			problem = err.Error()
+		}
+		if problem != "" {
+			Log.Infofp("⚠️", "%v needs attention: %v", repoName, problem)
+			attention = append(attention, fmt.Sprintf("%v: %v", repoName, problem))
+		}
+	}
+
+	if len(attention) > 0 {
+		return fmt.Errorf("%v of %v repositories need attention:\n  %v",
+			len(attention), len(issue.Repositories), strings.Join(attention, "\n  "))
+	}
+	Log.Infofp("👍", "All repositories of issue %v are in sync", issueID)
+	return nil
+}
+
+// syncRepository updates branch checked out in dir with its base branch.
+// It returns description of problem user has to resolve, like conflicts or uncommitted changes.
+func syncRepository(
+	ctx context.Context,
+	git GitClient,
+	syncer GitSyncer,
+	gitUser *GitUser,
+	profile *Profile,
+	dir string,
+	name RepoConfigName,
+	strategy string,
+	resume bool,
+) (string, error) {
+	if !fileExists(dir) {
+		return fmt.Sprintf("directory %v doesn't exist", dir), nil
+	}
+
+	// project config of repository can override base branch and strategy of profile
+	effective, err := EffectiveProfile(profile, dir)
+	if err != nil {
+		return "", err
+	}
+	if strategy == "" {
+		strategy = effective.SyncStrategy
+	}
+	if strategy == "" {
+		strategy = SyncRebase
+	}
+	if err := validateSyncStrategy(strategy); err != nil {
+		return "", err
+	}
+
+	inProgress, err := syncer.SyncInProgress(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	if inProgress != "" {
+		if !resume {
+			return fmt.Sprintf("%v in progress, resolve conflicts and run issuectl sync --continue", inProgress), nil
+		}
+		if conflicts, err := syncer.ConflictedFiles(ctx, dir); err != nil || len(conflicts) > 0 {
+			return conflictsProblem(conflicts), err
+		}
+		Log.Infofp("⏩", "Continuing %v of %v", inProgress, name)
+		if err := syncer.ContinueSync(ctx, dir, inProgress); err != nil {
+			return stoppedSyncProblem(ctx, syncer, dir, err)
+		}
+		Log.Infofp("✅", "%v synced", name)
+		return "", nil
+	}
+
+	if dirty, err := git.HasChanges(ctx, dir); err != nil {
+		return "", err
+	} else if dirty {
+		return "uncommitted changes, commit or stash them before syncing", nil
+	}
+
+	Log.Infofp("📥", "Fetching %v", name)
+	if err := git.Fetch(ctx, dir, gitUser); err != nil {
+		return "", err
+	}
+
+	base := effective.BaseBranch
+	if base == "" {
+		base = defaultBaseBranch
+	}
+	base = "origin/" + base
+	comparison, err := git.Compare(ctx, dir, base)
+	if err != nil {
+		return "", err
+	}
+	if comparison.Behind == 0 {
+		Log.Infofp("✅", "%v is up to date with %v", name, base)
+		return "", nil
+	}
+
+	Log.Infofp("🔄", "Updating %v with %v commits from %v using %v", name, comparison.Behind, base, strategy)
+	update := syncer.Rebase
+	if strategy == SyncMerge {
+		update = syncer.Merge
+	}
+	if err := update(ctx, dir, base); err != nil {
+		return stoppedSyncProblem(ctx, syncer, dir, err)
+	}
+	Log.Infofp("✅", "%v synced", name)
+	return "", nil
+}
+
+// stoppedSyncProblem describes why rebase or merge failed, returning err when it wasn't stopped by conflicts
+func stoppedSyncProblem(ctx context.Context, syncer GitSyncer, dir string, err error) (string, error) {
+	conflicts, conflictsErr := syncer.ConflictedFiles(ctx, dir)
+	if conflictsErr != nil || len(conflicts) == 0 {
+		return "", err
+	}
+	return conflictsProblem(conflicts), nil
+}
+
+func conflictsProblem(conflicts []string) string {
+	return fmt.Sprintf("conflicts in %v, resolve them, git add and run issuectl sync --continue", strings.Join(conflicts, ", "))
+}
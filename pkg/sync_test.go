@@ -0,0 +1,145 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startSyncTest starts issue with repository whose master gets commit changing README.md after issue branch was created
+func startSyncTest(t *testing.T, strategy string) (*testEnv, string) {
+	t.Helper()
+	env := newTestEnv(t, nil, nil, "service")
+	env.updateProfile(t, func(profile *Profile) { profile.SyncStrategy = strategy })
+	if err := StartWorkingOnIssue(context.Background(), "", env.config, "13"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ := env.config.GetIssue("13")
+
+	other := filepath.Join(t.TempDir(), "service")
+	runGitFixture(t, filepath.Dir(other), "clone", env.repos["service"].URL, other)
+	commitFile(t, other, "README.md", "# service\nupstream\n")
+	runGitFixture(t, other, "push", "origin", "HEAD:master")
+
+	return env, filepath.Join(issue.Dir, "service")
+}
+
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %v: %s", name, err)
+	}
+	runGitFixture(t, dir, "add", name)
+	runGitFixture(t, dir, "commit", "-m", "Change "+name)
+}
+
+func assertSynced(t *testing.T, dir string) {
+	t.Helper()
+	comparison, err := execGit{}.Compare(context.Background(), dir, "origin/master")
+	if err != nil {
+		t.Fatalf("Compare() failed: %s", err)
+	}
+	if comparison.Behind != 0 {
+		t.Errorf("expected branch to contain origin/master, it's %v commits behind", comparison.Behind)
+	}
+}
+
+// TestSyncIssue tests updating issue branch with both strategies.
+func TestSyncIssue(t *testing.T) {
+	for _, strategy := range []string{SyncRebase, SyncMerge} {
+		t.Run(strategy, func(t *testing.T) {
+			env, repoDir := startSyncTest(t, strategy)
+			commitFile(t, repoDir, "feature.go", "package feature\n")
+
+			if err := SyncIssue(context.Background(), env.config, "13", "", false); err != nil {
+				t.Fatalf("SyncIssue() failed: %s", err)
+			}
+			assertSynced(t, repoDir)
+
+			parents, err := gitOutput(context.Background(), repoDir, "log", "-1", "--format=%p")
+			if err != nil {
+				t.Fatalf("git log failed: %s", err)
+			}
+			if merged := len(strings.Fields(parents)) == 2; merged != (strategy == SyncMerge) {
+				t.Errorf("expected merge commit only with merge strategy, got parents %q", parents)
+			}
+		})
+	}
+}
+
+// TestSyncIssueConflict tests that sync stops on conflicts and continues after they are resolved.
+func TestSyncIssueConflict(t *testing.T) {
+	ctx := context.Background()
+	env, repoDir := startSyncTest(t, "")
+	commitFile(t, repoDir, "README.md", "# service\nlocal\n")
+
+	err := SyncIssue(ctx, env.config, "13", "", false)
+	if err == nil || !strings.Contains(err.Error(), "conflicts in README.md") {
+		t.Fatalf("expected conflicts in README.md, got %v", err)
+	}
+	if inProgress, _ := (execGit{}).SyncInProgress(ctx, repoDir); inProgress != SyncRebase {
+		t.Fatalf("expected rebase in progress, got %q", inProgress)
+	}
+
+	if err := SyncIssue(ctx, env.config, "13", "", false); err == nil || !strings.Contains(err.Error(), "rebase in progress") {
+		t.Errorf("expected rebase in progress error without --continue, got %v", err)
+	}
+	if err := SyncIssue(ctx, env.config, "13", "", true); err == nil || !strings.Contains(err.Error(), "conflicts in README.md") {
+		t.Errorf("expected unresolved conflicts error, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# service\nupstream\nlocal\n"), 0644); err != nil {
+		t.Fatalf("failed to resolve conflict: %s", err)
+	}
+	runGitFixture(t, repoDir, "add", "README.md")
+	if err := SyncIssue(ctx, env.config, "13", "", true); err != nil {
+		t.Fatalf("SyncIssue() with continue failed: %s", err)
+	}
+	if inProgress, _ := (execGit{}).SyncInProgress(ctx, repoDir); inProgress != "" {
+		t.Errorf("expected no operation in progress, got %q", inProgress)
+	}
+	assertSynced(t, repoDir)
+}
+
+// TestSyncIssueDirty tests that repositories with uncommitted changes are left untouched.
+func TestSyncIssueDirty(t *testing.T) {
+	env, repoDir := startSyncTest(t, "")
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	err := SyncIssue(context.Background(), env.config, "13", "", false)
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Errorf("expected uncommitted changes error, got %v", err)
+	}
+}
+
+// TestSyncIssueGoGit tests that sync reports go-git client can't rebase or merge.
+func TestSyncIssueGoGit(t *testing.T) {
+	env, _ := startSyncTest(t, "")
+	env.setGitClient(t, GitClientGoGit)
+
+	err := SyncIssue(context.Background(), env.config, "13", "", false)
+	if err == nil || !strings.Contains(err.Error(), "not supported by go-git git client") {
+		t.Fatalf("expected sync to be unsupported by go-git client, got %v", err)
+	}
+}
+
+// TestContinueSyncError tests that failure to continue sync is returned as GitError with output of git.
+func TestContinueSyncError(t *testing.T) {
+	fixture := newGitFixture(t, "service")
+	dir := filepath.Join(t.TempDir(), "service")
+	runGitFixture(t, filepath.Dir(dir), "clone", fixture.URL, dir)
+
+	err := (execGit{}).ContinueSync(context.Background(), dir, SyncRebase)
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("expected GitError, got %v", err)
+	}
+	if gitErr.Args[0] != SyncRebase || gitErr.Stderr == "" {
+		t.Errorf("expected GitError of rebase with git output, got %#v", gitErr)
+	}
+}
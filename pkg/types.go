@@ -42,15 +42,18 @@ type BackendConfig struct {
 	GitHub *GitHubConfig `yaml:"github,omitempty"`
 	GitLab *GitLabConfig `yaml:"gitlab,omitempty"`
 	Jira   *JiraConfig   `yaml:"jira,omitempty"`
+
+	// TokenSource tells where token is read from, when set token stored in backend specific config is ignored
+	TokenSource *SecretSource `yaml:"tokenSource,omitempty"`
 }
 
 type GitUserName string
 
 // GitUser holds config for git user
 type GitUser struct {
-	Name   GitUserName
-	Email  string
-	SSHKey string
+	Name   GitUserName `yaml:"name"`
+	Email  string      `yaml:"email"`
+	SSHKey string      `yaml:"sshKey"`
 }
 
 // RepoURL is a string with URL to git repo for cloning
@@ -69,6 +72,12 @@ type RepoConfig struct {
 
 	// URL to this repo
 	RepoURL RepoURL `yaml:"url"`
+
+	// Hooks are run at issue lifecycle events in repository directory
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// AllowProjectHooks runs also hooks from .issuectl.yaml of repository clone, enable only for trusted repositories
+	AllowProjectHooks bool `yaml:"allowProjectHooks,omitempty"`
 }
 
 // IssueID is a unique ID of issue in IssueBackend
@@ -84,6 +93,8 @@ type IssueConfig struct {
 	Repositories []RepoConfigName  `yaml:"repositories"`
 	Dir          string            `yaml:"dir"`
 	Profile      ProfileName       `yaml:"profile"`
+	// Launcher overrides launcher of profile for this issue
+	Launcher *LauncherConfig `yaml:"launcher,omitempty"`
 }
 
 type TextConfig struct {
@@ -1,107 +1,92 @@
 package issuectl
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
 )
 
-// cloneRepo takes a RepoConfig object, a directory name, and a GitUser object as arguments.
+// runCommand runs cmd and reports context error instead of the one caused by killing the process
+func runCommand(ctx context.Context, cmd *exec.Cmd) error {
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// cloneRepo takes a context, a GitClient, a RepoConfig object, a directory name, and a GitUser object as arguments.
 // It clones the repository URL from the RepoConfig into the specified directory,
 // and returns the path of the new repository directory and any error encountered.
-func cloneRepo(repo *RepoConfig, dir string, gitUser *GitUser) (string, error) {
+func cloneRepo(ctx context.Context, git GitClient, repo *RepoConfig, dir string, gitUser *GitUser) (string, error) {
+	if gitUser == nil {
+		return "", fmt.Errorf("git user for cloning %v not defined", repo.Name)
+	}
 	repoDir := filepath.Join(dir, string(repo.Name))
-	Log.V(3).Infof("git clone %v %v", repo.RepoURL, repoDir)
-	cmd := exec.Command("git", "clone", string(repo.RepoURL), repoDir)
-	if err := cmd.Run(); err != nil {
+	if err := git.Clone(ctx, repo.RepoURL, repoDir, CloneOptions{User: gitUser}); err != nil {
 		return "", err
 	}
 
-	if err := setRepoIdentity(repoDir, gitUser.Name, gitUser.Email, gitUser.SSHKey); err != nil {
+	if err := setRepoIdentity(ctx, git, repoDir, gitUser.Name, gitUser.Email, gitUser.SSHKey); err != nil {
 		return "", err
 	}
 
 	return repoDir, nil
 }
 
-// createBranch takes a directory, a branch name, and a GitUser object as arguments.
-// It creates a new git branch with the specified name in the specified directory.
+// createBranch takes a context, a GitClient, a directory, a branch name, a base branch and a GitUser object as arguments.
+// It creates a new git branch with the specified name in the specified directory, starting at base branch of origin,
+// or at HEAD of the clone when base is empty.
 // It returns any error encountered during the branch creation process.
-func createBranch(dir, branchName string, gitUser *GitUser) error {
-	if err := setRepoIdentity(dir, gitUser.Name, gitUser.Email, gitUser.SSHKey); err != nil {
+func createBranch(ctx context.Context, git GitClient, dir, branchName, base string, gitUser *GitUser) error {
+	if err := setRepoIdentity(ctx, git, dir, gitUser.Name, gitUser.Email, gitUser.SSHKey); err != nil {
 		return err
 	}
 
-	exists, err := branchExists(dir, branchName)
+	exists, err := git.BranchExists(ctx, dir, branchName)
 	if err != nil {
 		return err
 	}
 
 	if exists {
-		Log.V(3).Infof("git checkout %v", branchName)
-		cmd := exec.Command("git", "checkout", branchName)
-		cmd.Dir = dir
-		if err := cmd.Run(); err != nil {
-			return err
-		}
-	} else {
-		Log.V(3).Infof("git checkout -b %v", branchName)
-		cmd := exec.Command("git", "checkout", "-b", branchName)
-		cmd.Dir = dir
-		if err := cmd.Run(); err != nil {
+		return git.Checkout(ctx, dir, branchName, CheckoutOptions{})
+	}
+	opts := CheckoutOptions{Create: true}
+	if base != "" {
+		opts.StartPoint = "origin/" + base
+		found, err := git.RefExists(ctx, dir, opts.StartPoint)
+		if err != nil {
 			return err
 		}
-
-		Log.V(3).Infof("git push --set-upstream origin %v", branchName)
-		cmd = exec.Command("git", "push", "--set-upstream", "origin", branchName)
-		cmd.Dir = dir
-		if err := cmd.Run(); err != nil {
-			return err
+		if !found {
+			return fmt.Errorf("base branch %v not found in %v", base, filepath.Base(dir))
 		}
 	}
-
-	return nil
-}
-
-// branchExists checks if a branch exists in the repository located at dir.
-func branchExists(dir, branchName string) (bool, error) {
-	cmd := exec.Command("git", "branch", "--list", branchName)
-	cmd.Dir = dir
-	output, err := cmd.Output()
-	if err != nil {
-		return false, err
+	if err := git.Checkout(ctx, dir, branchName, opts); err != nil {
+		return err
 	}
-	return len(output) > 0, nil
+	return git.Push(ctx, dir, branchName, PushOptions{User: gitUser})
 }
 
 // setRepoIdentity sets local git config username, email and ssh command.
-func setRepoIdentity(dir string, username GitUserName, email, sshKeyPath string) error {
-	// Set local git config user.name
-	Log.V(3).Infof("git config user.name %v", username)
-	cmd := exec.Command("git", "config", "user.name", string(username))
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
-		return err
-	}
-
-	// Set local git config user.email
-	Log.V(3).Infof("git config user.email %v", email)
-	cmd = exec.Command("git", "config", "user.email", email)
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
-		return err
+func setRepoIdentity(ctx context.Context, git GitClient, dir string, username GitUserName, email, sshKeyPath string) error {
+	settings := [][2]string{
+		{"user.name", string(username)},
+		{"user.email", email},
+		{"core.sshCommand", fmt.Sprintf("ssh -i %s -F /dev/null", sshKeyPath)},
 	}
-
-	// Set local git config core.sshCommand
-	sshCommand := fmt.Sprintf("ssh -i %s -F /dev/null", sshKeyPath)
-	Log.V(3).Infof("git config core.sshCommand %v", sshCommand)
-	cmd = exec.Command("git", "config", "core.sshCommand", sshCommand)
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
-		return err
+	for _, setting := range settings {
+		if err := git.SetConfig(ctx, dir, setting[0], setting[1]); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
@@ -115,3 +100,26 @@ func createDirectory(parent, dirName string) (string, error) {
 	}
 	return dirPath, nil
 }
+
+// renderTemplate executes Go template text with data
+func renderTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %v: %w", name, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render %v: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// SortedKeys returns map keys in stable order
+func SortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -1,55 +1,138 @@
 package issuectl
 
 import (
-	"os"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// gitClients are implementations of GitClient tests run with
+var gitClients = []string{GitClientExec, GitClientGoGit}
+
+func newTestGitClient(t *testing.T, name string) GitClient {
+	t.Helper()
+	git, err := NewGitClient(name)
+	if err != nil {
+		t.Fatalf("NewGitClient(%v) failed: %s", name, err)
+	}
+	return git
+}
+
 // TestCloneRepo tests the cloneRepo function.
 func TestCloneRepo(t *testing.T) {
-	// Mocking the RepoConfig and GitUser
-	repo := &RepoConfig{Name: "testRepo", RepoURL: "https://github.com/janekbaraniewski/issuectl.git"}
-	gitUser := &GitUser{Name: "testUser", Email: "test@example.com", SSHKey: "/dev/null"}
+	for _, name := range gitClients {
+		t.Run(name, func(t *testing.T) {
+			// Mocking the RepoConfig and GitUser
+			fixture := newGitFixture(t, "testRepo")
+			repo := &RepoConfig{Name: "testRepo", RepoURL: RepoURL(fixture.URL)}
+			gitUser := &GitUser{Name: "testUser", Email: "test@example.com", SSHKey: "/dev/null"}
 
-	// Create a temporary directory to clone the repo
-	dir := os.TempDir()     //nolint:gosimple
-	defer os.RemoveAll(dir) // clean up
+			// Create a temporary directory to clone the repo
+			dir := t.TempDir()
 
-	// Call the cloneRepo function
-	_, err := cloneRepo(repo, dir, gitUser)
-	if err != nil {
-		t.Fatalf("cloneRepo() failed: %s", err)
+			// Call the cloneRepo function
+			repoDir, err := cloneRepo(context.Background(), newTestGitClient(t, name), repo, dir, gitUser)
+			if err != nil {
+				t.Fatalf("cloneRepo() failed: %s", err)
+			}
+			if email, err := gitOutput(context.Background(), repoDir, "config", "user.email"); err != nil || email != gitUser.Email {
+				t.Errorf("cloneRepo() set user.email %q, %v, want %q", email, err, gitUser.Email)
+			}
+		})
+	}
+}
+
+// TestCloneRepoError tests that git failures carry error output of git.
+func TestCloneRepoError(t *testing.T) {
+	repo := &RepoConfig{Name: "testRepo", RepoURL: RepoURL(filepath.Join(t.TempDir(), "missing.git"))}
+	gitUser := &GitUser{Name: "testUser", Email: "test@example.com", SSHKey: "/dev/null"}
+
+	_, err := cloneRepo(context.Background(), newTestGitClient(t, GitClientExec), repo, t.TempDir(), gitUser)
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("cloneRepo() error = %v, want *GitError", err)
+	}
+	if !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("cloneRepo() error %q doesn't contain output of git", err)
 	}
 }
 
 // TestCreateBranch tests the createBranch function.
 func TestCreateBranch(t *testing.T) {
-	t.Skip("FIXME")
-	// Mocks
-	repo := &RepoConfig{Name: "testRepo", RepoURL: "https://github.com/janekbaraniewski/issuectl.git"}
-	gitUser := &GitUser{Name: "testUser", Email: "test@example.com", SSHKey: "/dev/null"}
+	for _, name := range gitClients {
+		t.Run(name, func(t *testing.T) {
+			// Mocks
+			fixture := newGitFixture(t, "testRepo")
+			repo := &RepoConfig{Name: "testRepo", RepoURL: RepoURL(fixture.URL)}
+			gitUser := &GitUser{Name: "testUser", Email: "test@example.com", SSHKey: "/dev/null"}
+			git := newTestGitClient(t, name)
+			ctx := context.Background()
 
-	// Create a temporary directory to clone the repo
-	dir := os.TempDir()     //nolint:gosimple
-	defer os.RemoveAll(dir) // nolint
+			// Create a temporary directory to clone the repo
+			dir := t.TempDir()
 
-	// Call the cloneRepo function
-	repoDir, err := cloneRepo(repo, dir, gitUser)
-	if err != nil {
-		t.Fatalf("cloneRepo() failed: %s", err)
+			// Call the cloneRepo function
+			repoDir, err := cloneRepo(ctx, git, repo, dir, gitUser)
+			if err != nil {
+				t.Fatalf("cloneRepo() failed: %s", err)
+			}
+
+			// Call the createBranch function
+			if err := createBranch(ctx, git, repoDir, "testBranch", "", gitUser); err != nil {
+				t.Fatalf("createBranch() failed: %s", err)
+			}
+
+			if !fixture.hasBranch(t, "testBranch") {
+				t.Fatalf("createBranch() didn't push testBranch")
+			}
+			if branch, err := git.CurrentBranch(ctx, repoDir); err != nil || branch != "testBranch" {
+				t.Errorf("CurrentBranch() = %q, %v, want testBranch", branch, err)
+			}
+			if upstream, err := gitOutput(ctx, repoDir, "rev-parse", "--abbrev-ref", "@{upstream}"); err != nil || upstream != "origin/testBranch" {
+				t.Errorf("createBranch() set upstream %q, %v, want origin/testBranch", upstream, err)
+			}
+			if dirty, err := git.HasChanges(ctx, repoDir); err != nil || dirty {
+				t.Errorf("HasChanges() = %v, %v, want false", dirty, err)
+			}
+		})
 	}
+}
+
+// TestCreateBranchFromBase tests that branch starts at base branch of origin instead of HEAD of clone.
+func TestCreateBranchFromBase(t *testing.T) {
+	for _, name := range gitClients {
+		t.Run(name, func(t *testing.T) {
+			fixture := newGitFixture(t, "testRepo")
+			fixture.commit(t, "develop", nil)
+
+			repo := &RepoConfig{Name: "testRepo", RepoURL: RepoURL(fixture.URL)}
+			gitUser := &GitUser{Name: "testUser", Email: "test@example.com", SSHKey: "/dev/null"}
+			git := newTestGitClient(t, name)
+			ctx := context.Background()
+			repoDir, err := cloneRepo(ctx, git, repo, t.TempDir(), gitUser)
+			if err != nil {
+				t.Fatalf("cloneRepo() failed: %s", err)
+			}
 
-	// Call the createBranch function
-	if err := createBranch(repoDir, "testBranch", gitUser); err != nil {
-		t.Fatalf("createBranch() failed: %s", err)
+			if err := createBranch(ctx, git, repoDir, "testBranch", "develop", gitUser); err != nil {
+				t.Fatalf("createBranch() failed: %s", err)
+			}
+			if commit, err := git.LastCommit(ctx, repoDir); err != nil || commit.Subject != "Update develop" {
+				t.Errorf("expected branch to start at develop, got %v, %v", commit, err)
+			}
+			if err := createBranch(ctx, git, repoDir, "otherBranch", "missing", gitUser); err == nil || !strings.Contains(err.Error(), "base branch missing not found") {
+				t.Errorf("expected missing base branch error, got %v", err)
+			}
+		})
 	}
 }
 
 // TestCreateDirectory tests the createDirectory function.
 func TestCreateDirectory(t *testing.T) {
 	// Create a temporary directory
-	parentDir := os.TempDir()     //nolint:gosimple
-	defer os.RemoveAll(parentDir) // nolint
+	parentDir := t.TempDir()
 
 	// Call the createDirectory function
 	_, err := createDirectory(parentDir, "testDir")
@@ -0,0 +1,254 @@
+package issuectl
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ValidationError lists all problems found in config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("config is invalid:\n  %v", strings.Join(e.Problems, "\n  "))
+}
+
+// newValidationError returns nil when there are no problems
+func newValidationError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	unique := []string{}
+	for i, problem := range problems {
+		if i == 0 || problems[i-1] != problem {
+			unique = append(unique, problem)
+		}
+	}
+	return &ValidationError{Problems: unique}
+}
+
+// Validate checks that all references between config entities are valid
+func (ic *issuectlConfig) Validate() error {
+	problems := []string{}
+
+	if ic.CurrentProfile != "" && ic.Profiles[ic.CurrentProfile] == nil {
+		problems = append(problems, fmt.Sprintf("current profile %v is not defined", ic.CurrentProfile))
+	}
+
+	for name, profile := range ic.Profiles {
+		if profile == nil {
+			problems = append(problems, fmt.Sprintf("profile %v is empty", name))
+			continue
+		}
+		if profile.Name != name {
+			problems = append(problems, fmt.Sprintf("profile %v is stored under name %v", profile.Name, name))
+		}
+		resolved, _, err := ic.ResolveProfile(name)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if resolved.MergeLists != "" && resolved.MergeLists != MergeListsAppend && resolved.MergeLists != MergeListsReplace {
+			problems = append(problems, fmt.Sprintf(
+				"profile %v has invalid mergeLists %q, use %v or %v", name, resolved.MergeLists, MergeListsAppend, MergeListsReplace,
+			))
+		}
+		problems = append(problems, validateProfile(ic, resolved)...)
+	}
+
+	for name, backend := range ic.Backends {
+		if backend == nil {
+			problems = append(problems, fmt.Sprintf("backend %v is empty", name))
+			continue
+		}
+		if backend.Name != name {
+			problems = append(problems, fmt.Sprintf("backend %v is stored under name %v", backend.Name, name))
+		}
+		problems = append(problems, validateBackend(backend)...)
+	}
+
+	for name, repo := range ic.Repositories {
+		if repo == nil {
+			problems = append(problems, fmt.Sprintf("repository %v is empty", name))
+			continue
+		}
+		if repo.Name != name {
+			problems = append(problems, fmt.Sprintf("repository %v is stored under name %v", repo.Name, name))
+		}
+		if repo.RepoURL == "" {
+			problems = append(problems, fmt.Sprintf("repository %v has no url", name))
+		}
+		for _, problem := range validateHooks(repo.Hooks) {
+			problems = append(problems, fmt.Sprintf("repository %v %v", name, problem))
+		}
+	}
+
+	for name, user := range ic.GitUsers {
+		if user == nil {
+			problems = append(problems, fmt.Sprintf("git user %v is empty", name))
+			continue
+		}
+		if user.Name != name {
+			problems = append(problems, fmt.Sprintf("git user %v is stored under name %v", user.Name, name))
+		}
+	}
+
+	for id, issue := range ic.Issues {
+		if issue == nil {
+			problems = append(problems, fmt.Sprintf("issue %v is empty", id))
+			continue
+		}
+		problems = append(problems, validateIssue(ic, issue)...)
+	}
+
+	return newValidationError(problems)
+}
+
+// ValidateProfile checks that everything profile references is defined
+func ValidateProfile(config IssuectlConfig, profile *Profile) error {
+	return newValidationError(validateProfile(config, profile))
+}
+
+func validateProfile(config IssuectlConfig, profile *Profile) []string {
+	problems := []string{}
+	prefix := fmt.Sprintf("profile %v", profile.Name)
+
+	if profile.Extends != "" {
+		if _, _, err := config.ResolveProfile(profile.Name); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if profile.WorkDir == "" {
+		problems = append(problems, fmt.Sprintf("%v has no workDir", prefix))
+	}
+	if profile.GitUserName == "" {
+		problems = append(problems, fmt.Sprintf("%v has no git user", prefix))
+	} else if _, found := config.GetGitUser(profile.GitUserName); !found {
+		problems = append(problems, fmt.Sprintf("%v uses git user %v which is not defined", prefix, profile.GitUserName))
+	}
+
+	for _, repoName := range profile.Repositories {
+		if config.GetRepository(repoName) == nil {
+			problems = append(problems, fmt.Sprintf("%v uses repository %v which is not defined", prefix, repoName))
+		}
+	}
+
+	if profile.DefaultRepository != "" {
+		if config.GetRepository(profile.DefaultRepository) == nil {
+			problems = append(problems, fmt.Sprintf(
+				"%v uses default repository %v which is not defined", prefix, profile.DefaultRepository,
+			))
+		}
+	} else if profile.IssueBackend != "" || profile.RepoBackend != "" {
+		problems = append(problems, fmt.Sprintf("%v uses backends but has no default repository", prefix))
+	}
+
+	for name, text := range map[string]string{
+		"branchTemplate": profile.BranchTemplate,
+		"prTemplate":     profile.PRTemplate,
+		"commitTemplate": profile.CommitTemplate,
+	} {
+		if _, err := template.New(name).Parse(text); err != nil {
+			problems = append(problems, fmt.Sprintf("%v has invalid %v: %v", prefix, name, err))
+		}
+	}
+
+	if err := validateLauncher(profile.Launcher); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", prefix, err))
+	}
+	if err := validateSession(profile.Session); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", prefix, err))
+	}
+	if err := validateGitClient(profile.GitClient); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", prefix, err))
+	}
+	if err := validateSyncStrategy(profile.SyncStrategy); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", prefix, err))
+	}
+	for _, problem := range validateHooks(profile.Hooks) {
+		problems = append(problems, fmt.Sprintf("%v %v", prefix, problem))
+	}
+
+	if err := ValidateProfileBackends(config, profile); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", prefix, err))
+	}
+	return problems
+}
+
+func validateBackend(backend *BackendConfig) []string {
+	prefix := fmt.Sprintf("backend %v", backend.Name)
+	switch backend.Type {
+	case BackendGithub:
+		if backend.GitHub == nil {
+			return []string{fmt.Sprintf("%v of type %v has no github config", prefix, backend.Type)}
+		}
+		if err := validateHostURL(backend.GitHub.Host); err != nil {
+			return []string{fmt.Sprintf("%v: %v", prefix, err)}
+		}
+	case BackendGitLab:
+		if backend.GitLab == nil {
+			return []string{fmt.Sprintf("%v of type %v has no gitlab config", prefix, backend.Type)}
+		}
+		if err := validateHostURL(backend.GitLab.Host); err != nil {
+			return []string{fmt.Sprintf("%v: %v", prefix, err)}
+		}
+	case BackendJira:
+		if backend.Jira == nil {
+			return []string{fmt.Sprintf("%v of type %v has no jira config", prefix, backend.Type)}
+		}
+		if err := validateHostURL(backend.Jira.Host); err != nil {
+			return []string{fmt.Sprintf("%v: %v", prefix, err)}
+		}
+	default:
+		return []string{fmt.Sprintf("%v has unsupported type %q", prefix, backend.Type)}
+	}
+	if backend.TokenSource != nil {
+		if err := backend.TokenSource.Validate(); err != nil {
+			return []string{fmt.Sprintf("%v: %v", prefix, err)}
+		}
+	}
+	return nil
+}
+
+// validateHostURL checks that host of backend is absolute http(s) URL, empty host means public service
+func validateHostURL(host string) error {
+	if host == "" {
+		return nil
+	}
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("host %q is not valid URL: %w", host, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("host %q has to be http or https URL, e.g. https://git.example.com", host)
+	}
+	return nil
+}
+
+func validateIssue(config IssuectlConfig, issue *IssueConfig) []string {
+	problems := []string{}
+	prefix := fmt.Sprintf("issue %v", issue.ID)
+
+	if config.GetProfile(issue.Profile) == nil {
+		problems = append(problems, fmt.Sprintf("%v uses profile %v which is not defined", prefix, issue.Profile))
+	}
+	for _, repoName := range issue.Repositories {
+		if config.GetRepository(repoName) == nil {
+			problems = append(problems, fmt.Sprintf("%v uses repository %v which is not defined", prefix, repoName))
+		}
+	}
+	for _, backendName := range []BackendConfigName{issue.IssueBackend, issue.RepoBackend} {
+		if backendName != "" && config.GetBackend(backendName) == nil {
+			problems = append(problems, fmt.Sprintf("%v uses backend %v which is not defined", prefix, backendName))
+		}
+	}
+	if err := validateLauncher(issue.Launcher); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", prefix, err))
+	}
+	return problems
+}
@@ -0,0 +1,87 @@
+package issuectl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidate tests the Validate function.
+func TestValidate(t *testing.T) {
+	config := GetConfig(
+		"missing",
+		map[RepoConfigName]*RepoConfig{
+			"repo": {Name: "repo", RepoURL: "git@example.com:repo.git"},
+		},
+		map[BackendConfigName]*BackendConfig{
+			"jira": {Name: "jira", Type: BackendJira},
+		},
+		map[GitUserName]*GitUser{},
+		map[ProfileName]*Profile{
+			"work": {
+				Name:              "work",
+				WorkDir:           "/work",
+				GitUserName:       "deleted-user",
+				IssueBackend:      "deleted-backend",
+				RepoBackend:       "jira",
+				Repositories:      []RepoConfigName{"repo", "deleted-repo"},
+				DefaultRepository: "repo",
+			},
+		},
+	)
+
+	err := config.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	expected := []string{
+		"current profile missing is not defined",
+		"profile work uses git user deleted-user which is not defined",
+		"profile work uses repository deleted-repo which is not defined",
+		"issue backend deleted-backend is not defined",
+		"backend jira of type jira has no jira config",
+	}
+	for _, problem := range expected {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected problem %q in:\n%v", problem, err)
+		}
+	}
+}
+
+// TestValidateValidConfig tests that config prepared for lifecycle tests is valid.
+func TestValidateValidConfig(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	if err := env.config.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}
+
+// TestVerifyBackendCredentials tests credentials check used by doctor.
+func TestVerifyBackendCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	if err := VerifyBackendCredentials(ctx, githubFixtureBackend(newAPIServer(t, "github/verify_credentials"))); err != nil {
+		t.Errorf("expected GitHub credentials to be valid, got %v", err)
+	}
+	if err := VerifyBackendCredentials(ctx, gitlabFixtureBackend(newAPIServer(t, "gitlab/verify_credentials"))); err != nil {
+		t.Errorf("expected GitLab credentials to be valid, got %v", err)
+	}
+	if err := VerifyBackendCredentials(ctx, jiraFixtureBackend(newAPIServer(t, "jira/verify_credentials_rejected"))); err == nil {
+		t.Errorf("expected Jira credentials to be rejected")
+	}
+}
+
+// TestValidateBackendHost tests that malformed host of backend is reported instead of failing when client is created.
+func TestValidateBackendHost(t *testing.T) {
+	backend := &BackendConfig{Name: "gh", Type: BackendGithub, GitHub: &GitHubConfig{Host: "github.example.com"}}
+	problems := validateBackend(backend)
+	if len(problems) != 1 || !strings.Contains(problems[0], "has to be http or https URL") {
+		t.Fatalf("expected problem with host, got %v", problems)
+	}
+	if _, err := getIssueBackendConfigurator(context.Background(), backend); err == nil {
+		t.Errorf("expected backend with malformed host to be rejected")
+	}
+}
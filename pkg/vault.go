@@ -0,0 +1,166 @@
+package issuectl
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvVaultPassphrase is environment variable used as vault passphrase instead of prompting for it
+	EnvVaultPassphrase = "ISSUECTL_VAULT_PASSPHRASE"
+
+	vaultVersion = 1
+	vaultKeySize = 32
+)
+
+func getDefaultVaultFilePath() string {
+	configDir, err := userConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "vault")
+}
+
+var DefaultVaultFilePath = getDefaultVaultFilePath()
+
+// VaultPassphrase returns passphrase for the vault. By default it's read from EnvVaultPassphrase,
+// CLI replaces it with interactive prompt.
+var VaultPassphrase = func(create bool) (string, error) {
+	passphrase := os.Getenv(EnvVaultPassphrase)
+	if passphrase == "" {
+		return "", fmt.Errorf("vault passphrase not provided, set %v", EnvVaultPassphrase)
+	}
+	return passphrase, nil
+}
+
+// cachedPassphrase keeps passphrase so that it's asked for at most once per run
+var cachedPassphrase string
+
+// vaultFile is on disk format of the vault
+type vaultFile struct {
+	Version int    `yaml:"version"`
+	Salt    []byte `yaml:"salt"`
+	Nonce   []byte `yaml:"nonce"`
+	Data    []byte `yaml:"data"`
+}
+
+// Vault is a local file with secrets encrypted using key derived from passphrase
+type Vault struct {
+	path       string
+	passphrase string
+	secrets    map[string]string
+}
+
+// OpenVault decrypts vault stored at path. Missing file results in empty vault.
+func OpenVault(path string) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read vault: %w", err)
+	}
+	exists := err == nil
+
+	passphrase := cachedPassphrase
+	if passphrase == "" {
+		passphrase, err = VaultPassphrase(!exists)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	vault := &Vault{path: path, passphrase: passphrase, secrets: map[string]string{}}
+	if !exists {
+		cachedPassphrase = passphrase
+		return vault, nil
+	}
+
+	file := &vaultFile{}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("vault %v is corrupted: %w", path, err)
+	}
+	if file.Version != vaultVersion {
+		return nil, fmt.Errorf("vault %v has unsupported version %v", path, file.Version)
+	}
+
+	gcm, err := vaultCipher(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault %v - wrong passphrase?", path)
+	}
+	if err := yaml.Unmarshal(plain, &vault.secrets); err != nil {
+		return nil, fmt.Errorf("vault %v is corrupted: %w", path, err)
+	}
+
+	cachedPassphrase = passphrase
+	return vault, nil
+}
+
+// Get returns secret stored under key
+func (v *Vault) Get(key string) (string, bool) {
+	secret, found := v.secrets[key]
+	return secret, found
+}
+
+// Set stores secret under key, call Save to persist it
+func (v *Vault) Set(key, secret string) {
+	v.secrets[key] = secret
+}
+
+// Delete removes secret stored under key, call Save to persist it
+func (v *Vault) Delete(key string) {
+	delete(v.secrets, key)
+}
+
+// Save encrypts vault with fresh salt and nonce and writes it to disk
+func (v *Vault) Save() error {
+	plain, err := yaml.Marshal(v.secrets)
+	if err != nil {
+		return err
+	}
+
+	file := &vaultFile{Version: vaultVersion, Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	gcm, err := vaultCipher(v.passphrase, file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(v.path, data, 0600)
+}
+
+func vaultCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, vaultKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
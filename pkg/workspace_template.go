@@ -0,0 +1,137 @@
+package issuectl
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/google/go-github/github"
+)
+
+// templateFileSuffix marks files of workspace template which are rendered as Go templates
+const templateFileSuffix = ".tmpl"
+
+// IssueDetails holds issue fields fetched from issue backend
+type IssueDetails struct {
+	Title       string
+	Description string
+	URL         string
+}
+
+// workspaceTemplateData is passed to templates of workspace files
+type workspaceTemplateData struct {
+	ID           IssueID
+	Name         string
+	Title        string
+	Description  string
+	URL          string
+	Branch       string
+	Dir          string
+	Profile      ProfileName
+	Repositories []string
+}
+
+// getIssueDetails gets issue from backend and reads its details
+func getIssueDetails(ctx context.Context, config IssuectlConfig, issueBackend IssueBackend, profile *Profile, issueID IssueID) (*IssueDetails, error) {
+	repo, err := defaultRepository(config, profile)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := issueBackend.GetIssue(ctx, repo.Owner, repo.Name, issueID)
+	if err != nil {
+		return nil, fmt.Errorf(errFailedToGetIssue, err)
+	}
+
+	details := &IssueDetails{}
+	switch t := issue.(type) {
+	default:
+		return nil, fmt.Errorf("Missing issue type")
+	case *github.Issue:
+		Log.V(5).Infof("%v", t)
+		details.Title = t.GetTitle()
+		details.Description = t.GetBody()
+		details.URL = t.GetHTMLURL()
+	case *jira.Issue:
+		Log.V(5).Infof("%v", t)
+		details.Title = t.Fields.Summary
+		details.Description = t.Fields.Description
+	}
+	if details.URL == "" {
+		if url, err := issueBackend.GetIssueURL(repo.Owner, repo.Name, issueID); err == nil {
+			details.URL = url
+		}
+	}
+	return details, nil
+}
+
+// validateTemplateDir checks that workspace template directory exists.
+// It's checked when starting issue rather than in config validation, as directory may be missing on other machines.
+func validateTemplateDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("templateDir %v doesn't exist", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("templateDir %v is not a directory", dir)
+	}
+	return nil
+}
+
+// renderWorkspaceTemplate copies files of template directory to issue directory.
+// Files with .tmpl suffix are rendered as Go templates with issue data and saved without the suffix.
+// Top level entries named like issue repositories are skipped so that they don't overwrite clones.
+func renderWorkspaceTemplate(templateDir, issueDir string, data workspaceTemplateData) error {
+	repositories := map[string]bool{}
+	for _, repo := range data.Repositories {
+		repositories[repo] = true
+	}
+	return filepath.WalkDir(templateDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(templateDir, path)
+		if err != nil {
+			return err
+		}
+		if name := strings.TrimSuffix(rel, templateFileSuffix); repositories[name] {
+			Log.Infofp("⚠️", "Skipping %v of workspace template, it has the same name as repository", rel)
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(issueDir, rel), 0755)
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(issueDir, rel)
+		if strings.HasSuffix(rel, templateFileSuffix) {
+			rendered, err := renderTemplate(rel, string(content), data)
+			if err != nil {
+				return err
+			}
+			content = []byte(rendered)
+			target = strings.TrimSuffix(target, templateFileSuffix)
+		}
+		Log.V(3).Infof("Writing %v", target)
+		return os.WriteFile(target, content, info.Mode().Perm())
+	})
+}
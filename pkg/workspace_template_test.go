@@ -0,0 +1,75 @@
+package issuectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWorkspaceTemplate tests that template files are rendered with issue details into new issue directory.
+func TestWorkspaceTemplate(t *testing.T) {
+	server := newAPIServer(t, "github/workspace_template")
+	backend := githubFixtureBackend(server)
+	env := newTestEnv(t, backend, backend, "service")
+
+	templateDir := t.TempDir()
+	files := map[string]string{
+		"NOTES.md.tmpl":              "# {{.ID}} {{.Title}}\n\n{{.Description}}\n\n{{.URL}}\n",
+		"Makefile":                   "test:\n\tgo test {{.ID}}\n",
+		"compose/override.yaml.tmpl": "name: {{.Branch}}\nrepos: {{range .Repositories}}{{.}} {{end}}\n",
+		"service/NOTES.md":           "named like repository\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(templateDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create template dir: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write template: %s", err)
+		}
+	}
+	env.updateProfile(t, func(profile *Profile) { profile.TemplateDir = templateDir })
+
+	if err := StartWorkingOnIssue(context.Background(), "", env.config, "42"); err != nil {
+		t.Fatalf("StartWorkingOnIssue() failed: %s", err)
+	}
+	issue, _ := env.config.GetIssue("42")
+
+	expected := map[string]string{
+		"NOTES.md":              "# 42 Fix login bug\n\nLogin fails with SSO\n\nhttps://github.com/owner/service/issues/42\n",
+		"Makefile":              "test:\n\tgo test {{.ID}}\n",
+		"compose/override.yaml": "name: 42-Fix-login-bug\nrepos: service \n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(issue.Dir, name))
+		if err != nil {
+			t.Errorf("file %v not created: %s", name, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("expected %v to be %q, got %q", name, content, string(data))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(issue.Dir, "NOTES.md.tmpl")); !os.IsNotExist(err) {
+		t.Errorf("template file copied with .tmpl suffix")
+	}
+	if _, err := os.Stat(filepath.Join(issue.Dir, "service", "NOTES.md")); !os.IsNotExist(err) {
+		t.Errorf("template entry named like repository copied into its clone")
+	}
+}
+
+// TestWorkspaceTemplateMissingDir tests that missing template directory fails start before workspace is created.
+func TestWorkspaceTemplateMissingDir(t *testing.T) {
+	env := newTestEnv(t, nil, nil, "service")
+	env.updateProfile(t, func(profile *Profile) { profile.TemplateDir = filepath.Join(t.TempDir(), "missing") })
+
+	err := StartWorkingOnIssue(context.Background(), "", env.config, "42")
+	if err == nil || !strings.Contains(err.Error(), "doesn't exist") {
+		t.Fatalf("expected missing templateDir error, got %v", err)
+	}
+	if _, found := env.config.GetIssue("42"); found {
+		t.Errorf("issue added despite missing templateDir")
+	}
+}